@@ -2,11 +2,11 @@ package commands
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/BurntSushi/gribble"
@@ -19,6 +19,7 @@ import (
 	"github.com/BurntSushi/xgbutil/xrect"
 
 	"github.com/xuanmingyi/wingo/focus"
+	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/misc"
 	"github.com/xuanmingyi/wingo/wallpaper"
@@ -35,6 +36,7 @@ var Env = gribble.New([]gribble.Command{
 	&Dale{},
 	&Float{},
 	&Focus{},
+	&FocusDirection{},
 	&FocusRaise{},
 	&FrameBorders{},
 	&FrameFull{},
@@ -59,13 +61,21 @@ var Env = gribble.New([]gribble.Command{
 	&MovePointer{},
 	&MovePointerRelative{},
 	&Raise{},
+	&ReloadConfig{},
 	&RemoveWorkspace{},
 	&RenameWorkspace{},
 	&Resize{},
 	&Restart{},
 	&Quit{},
+	&SetGaps{},
 	&SetLayout{},
+	&SetLayoutState{},
 	&SetOpacity{},
+	&SetPlacement{},
+	&SnapTo{},
+	&SwapDirection{},
+	&ScratchpadSend{},
+	&ScratchpadToggle{},
 	&Script{},
 	&ScriptConfig{},
 	&Shell{},
@@ -95,14 +105,23 @@ var Env = gribble.New([]gribble.Command{
 	&AutoMastersMore{},
 	&AutoMastersFewer{},
 
+	&ManualSplit{},
+	&ManualToggleSplit{},
+	&ManualMove{},
+	&ManualResize{},
+
 	&CycleClientChoose{},
 	&CycleClientHide{},
 	&CycleClientNext{},
 	&CycleClientPrev{},
 	&Input{},
+	&InputWithHistory{},
 	&Message{},
 	&SelectClient{},
 	&SelectWorkspace{},
+	&AppLauncher{},
+	&Menu{},
+	&MenuFile{},
 
 	&GetActive{},
 	&GetAllClients{},
@@ -121,6 +140,7 @@ var Env = gribble.New([]gribble.Command{
 	&GetHeadWidth{},
 	&GetHeadWorkspace{},
 	&GetLayout{},
+	&GetLayoutState{},
 	&GetWorkspace{},
 	&GetWorkspaceId{},
 	&GetWorkspaceList{},
@@ -148,6 +168,14 @@ var Env = gribble.New([]gribble.Command{
 
 	&SetColorWallpaper{},
 	&SetFileWallpaper{},
+
+	&SessionSave{},
+	&SessionLoad{},
+
+	&RegisterSet{},
+	&RegisterGet{},
+	&RegisterDel{},
+	&RegisterList{},
 })
 
 var (
@@ -155,12 +183,8 @@ var (
 	// sent and executed synchronously with respect to the X main event loop.
 	// This is necessary to allow asynchronous prompts to run and return
 	// values without locking up the rest of the window manager.
-	SafeExec = make(chan func() gribble.Value, 1)
-
-	// SafeReturn is the means through which a return value from a Gribble
-	// command is synchronously returned with respext to the X main event loop.
-	// See SafeExec.
-	SafeReturn = make(chan gribble.Value, 0)
+	// Return values are sent back by the functions themselves. (See syncRun.)
+	SafeExec = make(chan func(), 1)
 
 	// Regex for enforcing tag name constraints.
 	validTagName = regexp.MustCompile("^[-a-zA-Z0-9_]+$")
@@ -173,16 +197,41 @@ func init() {
 	Env.Verbose = false
 }
 
-var syncLock = new(sync.Mutex)
-
 // syncRun should wrap the execution of most Gribble commands to ensure
 // synchronous execution with respect to the main X event loop.
 func syncRun(f func() gribble.Value) gribble.Value {
-	syncLock.Lock()
-	defer syncLock.Unlock()
-
-	SafeExec <- f
-	return <-SafeReturn
+	ret := make(chan gribble.Value, 1)
+	SafeExec <- func() { ret <- f() }
+	return <-ret
+}
+
+// SyncRun is the same as syncRun, but for code outside of Gribble commands
+// that needs to look at Wingo's state from another goroutine.
+func SyncRun(f func() gribble.Value) gribble.Value {
+	return syncRun(f)
+}
+
+// RunOnMainLoop runs f in its own goroutine and blocks until it returns.
+// While waiting, it executes everything sent to SafeExec, so that Gribble
+// commands run by f don't wait on the main X event loop that is blocked on
+// f. This allows commands (i.e., in synchronous hooks) to finish before the
+// main event loop moves on to the next event.
+//
+// RunOnMainLoop must only be called from the main X event loop.
+func RunOnMainLoop(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	for {
+		select {
+		case exec := <-SafeExec:
+			exec()
+		case <-done:
+			return
+		}
+	}
 }
 
 type AddWorkspace struct {
@@ -800,6 +849,28 @@ func (cmd MovePointerRelative) Run() gribble.Value {
 	})
 }
 
+type ReloadConfig struct {
+	Help string `
+Reloads Wingo's configuration files without restarting. Key and mouse
+bindings, options, the theme, hooks and rules are all read again and applied
+to every client.
+
+If a configuration file can't be parsed, an error is shown and the old
+configuration is kept. Some options (like the initial workspaces) are only
+read when Wingo starts, so they still require a Restart.
+`
+}
+
+func (cmd ReloadConfig) Run() gribble.Value {
+	return syncRun(func() gribble.Value {
+		if err := wm.ReloadConfig(); err != nil {
+			wm.PopupError("%s", err)
+			return cmdError("%s", err)
+		}
+		return nil
+	})
+}
+
 type Restart struct {
 	Help string `
 Restarts Wingo in place using exec. This should be used to reload Wingo
@@ -829,6 +900,38 @@ func (cmd Quit) Run() gribble.Value {
 	})
 }
 
+type SetGaps struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	Inner     int         `param:"2"`
+	Outer     int         `param:"3"`
+	Help      string      `
+Sets the gaps used by the tiling layouts of the workspace specified by
+Workspace. Inner is the space, in pixels, between windows, and Outer is the
+space between windows and the edges of the workspace.
+
+Whether smart gaps are used is still determined by the "smart_gaps" option.
+These gaps are kept when the configuration is reloaded with ReloadConfig.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd SetGaps) Run() gribble.Value {
+	if cmd.Inner < 0 || cmd.Outer < 0 {
+		return cmdError("Gaps must not be negative, but got %d and %d.",
+			cmd.Inner, cmd.Outer)
+	}
+	return syncRun(func() gribble.Value {
+		withWorkspace(cmd.Workspace, func(wrk *workspace.Workspace) {
+			gaps := wrk.Gaps()
+			gaps.Inner, gaps.Outer = cmd.Inner, cmd.Outer
+			wrk.SetGaps(gaps)
+		})
+		return nil
+	})
+}
+
 type SetLayout struct {
 	Workspace gribble.Any `param:"1" types:"int,string"`
 	Name      string      `param:"2"`
@@ -853,6 +956,72 @@ func (cmd SetLayout) Run() gribble.Value {
 	})
 }
 
+type SetPlacement struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	Policy    string      `param:"2"`
+	Help      string      `
+Sets the policy used to place new floating windows on the workspace specified
+by Workspace. Policy may be one of "random", "pointer", "center", "cascade",
+"smart" or "parent". See "placement" in options.wini for what each of them
+does. This policy is kept when the configuration is reloaded with ReloadConfig.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd SetPlacement) Run() gribble.Value {
+	p, ok := layout.ParsePlacement(cmd.Policy)
+	if !ok {
+		return cmdError("Unknown placement policy '%s'.", cmd.Policy)
+	}
+	return syncRun(func() gribble.Value {
+		withWorkspace(cmd.Workspace, func(wrk *workspace.Workspace) {
+			wrk.SetPlacement(p)
+		})
+		return nil
+	})
+}
+
+type SetLayoutState struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	State     string      `param:"2"`
+	Help      string      `
+Restores the state of a tiling layout on the workspace specified by Workspace.
+State should be a value returned by GetLayoutState. The state is given to the
+layout named in State, and if the workspace is using a tiling layout, it is
+switched to that layout.
+
+Clients in State that are no longer on the workspace are ignored, and clients
+that aren't in State are put after the others. Parts of the state missing from
+State (like Masters) are left unchanged, so a script may pass only Clients to
+reorder the windows.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd SetLayoutState) Run() gribble.Value {
+	var state layoutState
+	if err := json.Unmarshal([]byte(cmd.State), &state); err != nil {
+		return cmdError("Could not read layout state: %s", err)
+	}
+	return syncRun(func() gribble.Value {
+		var err gribble.Value
+		withWorkspace(cmd.Workspace, func(wrk *workspace.Workspace) {
+			if !wrk.SetLayoutState(state.Layout, state.State) {
+				err = cmdError("Unknown tiling layout '%s'.", state.Layout)
+				return
+			}
+			if wrk.State == workspace.AutoTiling {
+				wrk.SetLayout(state.Layout)
+			}
+		})
+		return err
+	})
+}
+
 type SetOpacity struct {
 	Client  gribble.Any `param:"1" types:"int,string"`
 	Opacity float64     `param:"2"`
@@ -885,6 +1054,89 @@ func (cmd SetOpacity) Run() gribble.Value {
 	})
 }
 
+type ScratchpadSend struct {
+	Client gribble.Any `param:"1" types:"int,string"`
+	Help   string      `
+Hides the window specified by Client in the scratchpad, which is a workspace
+that is never shown. From then on, the window always floats, and
+ScratchpadToggle can be used to show and hide it.
+
+Client may be the window id or a substring that matches a window name.
+`
+}
+
+func (cmd ScratchpadSend) Run() gribble.Value {
+	return syncRun(func() gribble.Value {
+		return withClient(cmd.Client, func(c *xclient.Client) {
+			c.ScratchpadSend()
+		})
+	})
+}
+
+type ScratchpadToggle struct {
+	Name    string `param:"1"`
+	Command string `param:"2"`
+	Help    string `
+Shows the scratchpad window matching Name centered on the active monitor,
+above every other window. If it is already shown on the active workspace, it
+is hidden in the scratchpad again.
+
+A window matches Name if Name is its instance or class (case insensitive), or
+if Name is part of its title. Using the instance or class is recommended,
+since titles tend to change. An empty Name matches any scratchpad window.
+
+When no scratchpad window matches Name and Command is not empty, Command is
+run in a shell. The first new window that matches Name becomes a scratchpad
+window and is shown. For example, to toggle a drop-down terminal:
+
+	ScratchpadToggle "scratchterm" "xterm -name scratchterm"
+`
+}
+
+func (cmd ScratchpadToggle) Run() gribble.Value {
+	if len(cmd.Command) > 0 && len(cmd.Name) == 0 {
+		return cmdError("A Name is needed to start a scratchpad window.")
+	}
+	return syncRun(func() gribble.Value {
+		if !xclient.ScratchpadToggle(cmd.Name) && len(cmd.Command) > 0 {
+			xclient.ScratchpadExpect(cmd.Name)
+			Shell{Command: cmd.Command}.Run()
+		}
+		return nil
+	})
+}
+
+type SnapTo struct {
+	Client gribble.Any `param:"1" types:"int,string"`
+	Region string      `param:"2"`
+	Help   string      `
+Tiles the floating window specified by Client to a part of its monitor (not
+counting docks and panels). Region may be one of "left", "right", "top" or
+"bottom" for a half of the monitor, "top-left", "top-right", "bottom-left" or
+"bottom-right" for a quarter of the monitor, or "center" to center the window.
+
+The size the window had before it was first snapped is remembered: "center"
+uses it, and the window gets it back when it is dragged away with the mouse.
+
+This command has no effect on tiled windows.
+
+Client may be the window id or a substring that matches a window name.
+`
+}
+
+func (cmd SnapTo) Run() gribble.Value {
+	region, ok := layout.ParseSnapRegion(cmd.Region)
+	if !ok {
+		return cmdError("Unknown snap region '%s'.", cmd.Region)
+	}
+	return syncRun(func() gribble.Value {
+		withClient(cmd.Client, func(c *xclient.Client) {
+			c.SnapTo(region)
+		})
+		return nil
+	})
+}
+
 type RemoveWorkspace struct {
 	Workspace gribble.Any `param:"1" types:"int,string"`
 	Help      string      `
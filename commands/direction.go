@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"github.com/BurntSushi/gribble"
+	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/wm"
+	"github.com/xuanmingyi/wingo/workspace"
+	"github.com/xuanmingyi/wingo/xclient"
+)
+
+type FocusDirection struct {
+	Direction string `param:"1"`
+	Heads     string `param:"2"`
+	Help      string `
+Focuses and raises the window closest to the focused window in the direction
+given by Direction, which may be one of "left", "right", "up" or "down". Both
+tiled and floating windows on the current workspace are considered.
+
+When Heads is "yes" and there is no window in that direction, the window
+closest in that direction on the next head over is focused instead. If that
+head has no windows, its workspace is simply focused. Valid values for Heads
+are "yes" or "no".
+`
+}
+
+func (cmd FocusDirection) Run() gribble.Value {
+	dir, ok := layout.ParseDirection(cmd.Direction)
+	if !ok {
+		return cmdError("Unknown direction '%s'.", cmd.Direction)
+	}
+	return syncRun(func() gribble.Value {
+		from, focused := directionOrigin()
+		wrk := wm.Workspace()
+		target := closestClient(from, dir,
+			directionClients(wrk, func(c *xclient.Client) bool {
+				return c != focused
+			}))
+		if target == nil && stringBool(cmd.Heads) {
+			wrk = headInDirection(wrk, dir)
+			if wrk == nil {
+				return nil
+			}
+			wm.SetWorkspace(wrk, false)
+			target = closestClient(from, dir, directionClients(wrk, nil))
+			if target == nil {
+				wm.FocusFallback()
+				return nil
+			}
+		}
+		if target != nil {
+			target.Focus()
+			target.Raise()
+		}
+		return nil
+	})
+}
+
+type SwapDirection struct {
+	Direction string `param:"1"`
+	Heads     string `param:"2"`
+	Help      string `
+Switches the places of the focused window and the window closest to it in the
+direction given by Direction, which may be one of "left", "right", "up" or
+"down".
+
+A tiled window is only switched with another window in the same tiling
+layout, and takes its place in the order of the layout. A floating window is
+only switched with another floating window, and the two windows trade
+positions and sizes.
+
+When Heads is "yes" and there is no window in that direction, the focused
+window is moved to the workspace on the next head over instead. Valid values
+for Heads are "yes" or "no".
+`
+}
+
+func (cmd SwapDirection) Run() gribble.Value {
+	dir, ok := layout.ParseDirection(cmd.Direction)
+	if !ok {
+		return cmdError("Unknown direction '%s'.", cmd.Direction)
+	}
+	return syncRun(func() gribble.Value {
+		from, focused := directionOrigin()
+		if focused == nil {
+			return nil
+		}
+		wrk := wm.Workspace()
+		lay := focused.Layout()
+		target := closestClient(from, dir,
+			directionClients(wrk, func(c *xclient.Client) bool {
+				return c != focused && c.Layout() == lay
+			}))
+		if target == nil {
+			if !stringBool(cmd.Heads) || focused.IsSticky() {
+				return nil
+			}
+			if wrk = headInDirection(wrk, dir); wrk != nil {
+				wm.SetWorkspace(wrk, false)
+				wrk.Add(focused)
+				focused.Focus()
+				focused.Raise()
+			}
+			return nil
+		}
+
+		if tiler, ok := lay.(layout.AutoTiler); ok {
+			layout.SwapClients(tiler, focused, target)
+		} else {
+			focused.EnsureUnmax()
+			target.EnsureUnmax()
+			g1, g2 := focused.Geom(), target.Geom()
+			focused.LayoutMoveResize(xrect.Pieces(g2))
+			target.LayoutMoveResize(xrect.Pieces(g1))
+		}
+		focused.Raise()
+		return nil
+	})
+}
+
+// directionOrigin returns the focused client and its geometry. If no client
+// is focused, the geometry of the current workspace is returned instead.
+func directionOrigin() (xrect.Rect, *xclient.Client) {
+	if focused := wm.LastFocused(); focused != nil {
+		c := focused.(*xclient.Client)
+		return c.Geom(), c
+	}
+	return wm.Workspace().Geom(), nil
+}
+
+// directionClients returns every client that is visible on wrk (including
+// sticky clients) for which keep returns true. If keep is nil, every visible
+// client is returned.
+func directionClients(wrk *workspace.Workspace,
+	keep func(c *xclient.Client) bool) []*xclient.Client {
+
+	clients := make([]*xclient.Client, 0)
+	for _, client := range wm.Clients {
+		c := client.(*xclient.Client)
+		if !c.IsMapped() || c.Iconified() {
+			continue
+		}
+		if c.Workspace() != wrk && c.Workspace() != wm.StickyWrk {
+			continue
+		}
+		if keep == nil || keep(c) {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
+
+// closestClient returns the client closest to from in the direction given,
+// or nil if there is no client in that direction.
+func closestClient(from xrect.Rect, dir layout.Direction,
+	clients []*xclient.Client) *xclient.Client {
+
+	rects := make([]xrect.Rect, len(clients))
+	for i, c := range clients {
+		rects[i] = c.Geom()
+	}
+	if i := layout.Closest(from, dir, rects); i > -1 {
+		return clients[i]
+	}
+	return nil
+}
+
+// headInDirection returns the visible workspace on the head closest to the
+// head of wrk in the direction given, or nil if there is no such head.
+func headInDirection(wrk *workspace.Workspace,
+	dir layout.Direction) *workspace.Workspace {
+
+	visibles := wm.Heads.VisibleWorkspaces()
+	rects := make([]xrect.Rect, len(visibles))
+	for i, vis := range visibles {
+		rects[i] = vis.HeadGeom()
+	}
+	if i := layout.Closest(wrk.HeadGeom(), dir, rects); i > -1 {
+		return visibles[i]
+	}
+	return nil
+}
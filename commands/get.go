@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,7 @@ import (
 
 	"github.com/BurntSushi/gribble"
 
+	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/wm"
 	"github.com/xuanmingyi/wingo/workspace"
@@ -365,6 +367,60 @@ func (cmd GetLayout) Run() gribble.Value {
 	})
 }
 
+// layoutState is the value returned by GetLayoutState and accepted by
+// SetLayoutState.
+type layoutState struct {
+	Layout string
+	layout.State
+}
+
+// UnmarshalJSON decodes Layout and State separately, since the UnmarshalJSON
+// method of the embedded State would otherwise decode the whole value.
+func (s *layoutState) UnmarshalJSON(bs []byte) error {
+	var name struct{ Layout string }
+	if err := json.Unmarshal(bs, &name); err != nil {
+		return err
+	}
+	s.Layout = name.Layout
+	return json.Unmarshal(bs, &s.State)
+}
+
+type GetLayoutState struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	Help      string      `
+Returns the state of the tiling layout on the workspace specified by
+Workspace, so that it can be restored later with SetLayoutState. If the
+workspace is floating, the state of the tiling layout it would use is
+returned.
+
+The state is a JSON object with the name of the layout (Layout), the number of
+masters (Masters), the proportion of the screen given to the masters
+(MasterProportion), the proportion of each window in its split (Proportions)
+and the ids of the windows in the layout, in order (Clients).
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd GetLayoutState) Run() gribble.Value {
+	return syncRun(func() gribble.Value {
+		var state *layoutState
+		withWorkspace(cmd.Workspace, func(wrk *workspace.Workspace) {
+			lay := wrk.LayoutAutoTiler()
+			state = &layoutState{Layout: lay.Name(), State: lay.State()}
+		})
+		if state == nil {
+			return ""
+		}
+		bs, err := json.Marshal(state)
+		if err != nil {
+			return cmdError("Could not encode layout state: %s", err)
+		}
+		return string(bs)
+	})
+}
+
 type GetWorkspace struct {
 	Help string `
 Returns the name of the current workspace.
@@ -91,8 +91,8 @@ func intToBool(n int) bool {
 }
 
 // stringTabComp takes a string and converts it to a tab completion constant
-// defined in the prompt package. Valid values are "Prefix", "Any" and
-// "Multiple"
+// defined in the prompt package. Valid values are "Prefix", "Any",
+// "Multiple" and "Fuzzy".
 func stringTabComp(s string) int {
 	switch s {
 	case "Prefix":
@@ -101,6 +101,8 @@ func stringTabComp(s string) int {
 		return prompt.TabCompleteAny
 	case "Multiple":
 		return prompt.TabCompleteMultiple
+	case "Fuzzy":
+		return prompt.TabCompleteFuzzy
 	default:
 		logger.Warning.Printf(
 			"Tab completion mode '%s' not supported.", s)
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/gribble"
 
+	"github.com/xuanmingyi/wingo/launcher"
 	"github.com/xuanmingyi/wingo/prompt"
 	"github.com/xuanmingyi/wingo/wm"
 	"github.com/xuanmingyi/wingo/workspace"
@@ -118,23 +122,55 @@ input box will be returned.
 
 Label will be shown next to the input box.
 
+Text entered before can be recalled with the Up and Down keys, or found by
+pressing Control-r and typing part of it. (Press Control-r again to find older
+matches.) This history is shared with every other use of Input, and is kept
+across restarts. (See the "input_history" option.) Use InputWithHistory to
+keep a separate history.
+
 This command may be used as a sub-command to pass user provided arguments to
 another command.
 `
 }
 
 func (cmd Input) Run() gribble.Value {
+	return showInput(cmd.Label, "")
+}
+
+type InputWithHistory struct {
+	Label   string `param:"1"`
+	History string `param:"2"`
+	Help    string `
+Exactly the same as Input, except the text entered is remembered in the
+history named by History, instead of the history shared by every use of Input.
+This keeps unrelated histories apart, like the commands run with Shell and the
+names given to workspaces.
+`
+}
+
+func (cmd InputWithHistory) Run() gribble.Value {
+	return showInput(cmd.Label, cmd.History)
+}
+
+// showInput shows the input prompt with the history named history, and
+// returns the text entered (or an empty string if the prompt was canceled).
+func showInput(label, history string) string {
 	inputted := make(chan string, 0)
 
 	response := func(inp *prompt.Input, text string) {
+		wm.SaveInputHistories()
 		inputted <- text
 		inp.Hide()
 	}
 	canceled := func(inp *prompt.Input) {
 		inputted <- ""
 	}
-	geom := wm.Workspace().Geom()
-	if !wm.Prompts.Input.Show(geom, cmd.Label, response, canceled) {
+	shown := syncRun(func() gribble.Value {
+		geom := wm.Workspace().Geom()
+		return wm.Prompts.Input.ShowHistory(geom, label,
+			wm.InputHistory(history), response, canceled)
+	})
+	if !shown.(bool) {
 		return ""
 	}
 
@@ -173,11 +209,14 @@ Valid values are "yes" or "no".
 ShowIconified specifies that iconified clients will be shown. Valid values are
 "yes" or "no".
 
-TabCompletetion can be set to either "Prefix", "Any" or "Multiple". When it's
-set to "Prefix", the clients can be searched by a prefix matching string. When
-it's set to "Any", the clients can be searched by a substring matching string.
-When it's set to "Multiple", the clients can be searched by multiple space-
-separated substring matching strings.
+TabCompletetion can be set to either "Prefix", "Any", "Multiple" or "Fuzzy".
+When it's set to "Prefix", the clients can be searched by a prefix matching
+string. When it's set to "Any", the clients can be searched by a substring
+matching string. When it's set to "Multiple", the clients can be searched by
+multiple space-separated substring matching strings. When it's set to "Fuzzy",
+the clients can be searched by typing any of their characters in order. Fuzzy
+matches are ordered by how well they match (at the start of words and in runs
+of consecutive characters are best) and the best match is selected.
 
 This command may be used as a sub-command to pass a particular client to
 another command.
@@ -218,11 +257,14 @@ type SelectWorkspace struct {
 	Help string `
 Shows a centered prompt window with a list of all workspaces.
 
-TabCompletetion can be set to either "Prefix", "Any" or "Multiple". When it's
-set to "Prefix", the clients can be searched by a prefix matching string. When
-it's set to "Any", the clients can be searched by a substring matching string.
-When it's set to "Multiple", the clients can be searched by multiple space-
-separated substring matching strings.
+TabCompletetion can be set to either "Prefix", "Any", "Multiple" or "Fuzzy".
+When it's set to "Prefix", the clients can be searched by a prefix matching
+string. When it's set to "Any", the clients can be searched by a substring
+matching string. When it's set to "Multiple", the clients can be searched by
+multiple space-separated substring matching strings. When it's set to "Fuzzy",
+the clients can be searched by typing any of their characters in order. Fuzzy
+matches are ordered by how well they match (at the start of words and in runs
+of consecutive characters are best) and the best match is selected.
 
 This command may be used as a sub-command to pass a particular workspace to
 another command.
@@ -253,3 +295,184 @@ func (cmd SelectWorkspace) Run() gribble.Value {
 	panic("unreachable")
 }
 
+type AppLauncher struct {
+	Help string `
+Shows a centered prompt window with a list of applications and commands that
+can be launched. Applications are read from the desktop entries (.desktop
+files) in the "applications" directory of each XDG data directory, and
+commands are the executables found in $PATH.
+
+The list is searched by fuzzy matching: an application matches when every
+character typed appears in its name, in the same order. Applications that are
+launched often and that were launched recently are listed first. (See the
+"launcher_history" option.)
+
+The chosen application is run with the shell, just like with the Shell
+command. Applications that must be run in a terminal are run in the terminal
+given by the "terminal" option.
+
+The command used to launch the application is returned, or an empty string if
+the prompt was canceled.
+`
+}
+
+func (cmd AppLauncher) Run() gribble.Value {
+	selected := make(chan *launcher.Entry, 1)
+
+	data := wm.LauncherData{
+		Selected: func(entry *launcher.Entry) {
+			selected <- entry
+		},
+	}
+	syncRun(func() gribble.Value {
+		wm.ShowAppLauncher(data)
+		return nil
+	})
+
+	for {
+		select {
+		case entry := <-selected:
+			syncRun(func() gribble.Value {
+				wm.LauncherUsed(entry)
+				return nil
+			})
+			command := entry.Exec
+			if entry.Terminal {
+				command = fmt.Sprintf("%s -e %s", wm.Config.Terminal, command)
+			}
+			Shell{Command: command}.Run()
+			return command
+		case <-time.After(10 * time.Second):
+			if !wm.Prompts.Slct.Showing() {
+				return ""
+			}
+		}
+	}
+	panic("unreachable")
+}
+
+type Menu struct {
+	TabCompletion string `param:"1"`
+	Items string `param:"2"`
+	Help string `
+Shows a centered prompt window with a menu of the items given, and returns the
+text of the item chosen. An empty string is returned if the prompt was
+canceled.
+
+Items is a list of items separated by "\n". An item may be followed by "\t"
+and the path of an image file, which is shown as the icon of the item. A line
+starting with "#" starts a new group of items, with the rest of the line as
+its heading. For example:
+
+	"#Session\nLock\t/usr/share/pixmaps/lock.png\nLog out\n#Power\nReboot"
+
+TabCompletion is the same as for SelectClient: "Prefix", "Any", "Multiple" or
+"Fuzzy".
+
+This command is meant to be used from scripts with wingo-cmd. See MenuFile for
+a version that reads the menu from a file.
+`
+}
+
+func (cmd Menu) Run() gribble.Value {
+	if len(cmd.Items) == 0 {
+		return ""
+	}
+	items := strings.Replace(fixScannerBugs(cmd.Items), "\\n", "\n", -1)
+	items = strings.Replace(items, "\\t", "\t", -1)
+	return showMenu(cmd.TabCompletion, parseMenu(items))
+}
+
+type MenuFile struct {
+	TabCompletion string `param:"1"`
+	File string `param:"2"`
+	Help string `
+Shows a centered prompt window with a menu of the items in File, and returns
+the text of the item chosen. An empty string is returned if the prompt was
+canceled or if File could not be read.
+
+File has the same format as the items of the Menu command, except that items
+are on their own lines and icons are separated from items with a tab.
+
+TabCompletion is the same as for SelectClient: "Prefix", "Any", "Multiple" or
+"Fuzzy".
+`
+}
+
+func (cmd MenuFile) Run() gribble.Value {
+	bs, err := ioutil.ReadFile(cmd.File)
+	if err != nil {
+		return cmdError("Could not read menu file '%s': %s", cmd.File, err)
+	}
+	return showMenu(cmd.TabCompletion, parseMenu(string(bs)))
+}
+
+// showMenu shows a menu with the groups given and waits for an item to be
+// chosen. The text of the item is returned, or an empty string if the menu
+// was canceled.
+func showMenu(tabComp string, groups []wm.MenuGroup) gribble.Value {
+	selected := make(chan string, 1)
+
+	data := wm.MenuData{
+		Selected: func(item wm.MenuItem) {
+			selected <- item.Text
+		},
+	}
+	shown := syncRun(func() gribble.Value {
+		if wm.ShowMenu(groups, stringTabComp(tabComp), data) {
+			return "yes"
+		}
+		return "no"
+	})
+	if shown != "yes" {
+		return ""
+	}
+
+	for {
+		select {
+		case text := <-selected:
+			return text
+		case <-time.After(10 * time.Second):
+			if !wm.Prompts.Slct.Showing() {
+				return ""
+			}
+		}
+	}
+	panic("unreachable")
+}
+
+// parseMenu returns the groups of items described by s. Each line of s is an
+// item, optionally followed by a tab and the path of its icon. Lines starting
+// with "#" start a new group, and blank lines are ignored.
+func parseMenu(s string) []wm.MenuGroup {
+	groups := []wm.MenuGroup{{}}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		if line[0] == '#' {
+			heading := strings.TrimSpace(line[1:])
+			groups = append(groups, wm.MenuGroup{Heading: heading})
+			continue
+		}
+
+		item := wm.MenuItem{Text: line}
+		if i := strings.Index(line, "\t"); i > -1 {
+			item.Text = line[:i]
+			item.Icon = strings.TrimSpace(line[i+1:])
+		}
+		last := &groups[len(groups)-1]
+		last.Items = append(last.Items, item)
+	}
+
+	// Groups without any items aren't shown. (The first group is only used
+	// for items listed before any heading.)
+	nonEmpty := groups[:0]
+	for _, group := range groups {
+		if len(group.Items) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
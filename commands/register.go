@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/gribble"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/register"
+	"github.com/xuanmingyi/wingo/wm"
+)
+
+type RegisterSet struct {
+	Key   string      `param:"1"`
+	Value gribble.Any `param:"2" types:"int,float,string"`
+	Help  string      `
+Stores Value in the register under the name Key. Values in the register
+persist across Wingo restarts, so they can be used by hooks and scripts to
+remember things like counters, the last used workspace or per application
+preferences.
+
+Value may be an integer, a float or a string, and RegisterGet will return it
+with the same type. It is returned unchanged.
+
+The register database is set with the "register_url" option in options.wini.
+`
+}
+
+func (cmd RegisterSet) Run() gribble.Value {
+	if wm.Register == nil {
+		return cmdError("The register is not available.")
+	}
+	if err := wm.Register.Set(cmd.Key, cmd.Value); err != nil {
+		return cmdError("Could not set '%s': %s", cmd.Key, err)
+	}
+	return cmd.Value
+}
+
+type RegisterGet struct {
+	Key  string `param:"1"`
+	Help string `
+Returns the value stored in the register under the name Key. If there is
+no such value, an empty string is returned.
+
+The register database is set with the "register_url" option in options.wini.
+`
+}
+
+func (cmd RegisterGet) Run() gribble.Value {
+	if wm.Register == nil {
+		return cmdError("The register is not available.")
+	}
+	record, err := wm.Register.Get(cmd.Key)
+	if err != nil {
+		logger.Lots.Println(err)
+		return ""
+	}
+	return registerValue(record)
+}
+
+type RegisterDel struct {
+	Key  string `param:"1"`
+	Help string `
+Removes the value stored in the register under the name Key. This command has
+no effect if there is no such value.
+
+The register database is set with the "register_url" option in options.wini.
+`
+}
+
+func (cmd RegisterDel) Run() gribble.Value {
+	if wm.Register == nil {
+		return cmdError("The register is not available.")
+	}
+	if err := wm.Register.Del(cmd.Key); err != nil {
+		return cmdError("Could not delete '%s': %s", cmd.Key, err)
+	}
+	return nil
+}
+
+type RegisterList struct {
+	Prefix string `param:"1"`
+	Help   string `
+Returns a list of all keys in the register that start with Prefix, sorted and
+separated by new lines. Use an empty Prefix to list every key.
+
+The register database is set with the "register_url" option in options.wini.
+`
+}
+
+func (cmd RegisterList) Run() gribble.Value {
+	if wm.Register == nil {
+		return cmdError("The register is not available.")
+	}
+	return strings.Join(wm.Register.Keys(cmd.Prefix), "\n")
+}
+
+// registerValue converts a record from the register into a value that can
+// be returned by a Gribble command.
+func registerValue(record register.Record) gribble.Value {
+	switch v := record.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		return v
+	case bool:
+		return boolToInt(v)
+	case []register.Record:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprintf("%v", registerValue(item))
+		}
+		return strings.Join(items, "\n")
+	}
+	logger.Warning.Printf("Unknown register value type: %T", record)
+	return ""
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"github.com/BurntSushi/gribble"
+
+	"github.com/xuanmingyi/wingo/wm"
+	"github.com/xuanmingyi/wingo/xclient"
+)
+
+type SessionSave struct {
+	File string `param:"1"`
+	Help string `
+Saves the workspace, floating/tiling status, frame, geometry, maximized,
+sticky and iconified state of every window to the session file specified by
+File. When a window in the session shows up again (even after Wingo has been
+restarted or you've logged out), it will be put back where it was. The layout
+of every workspace and the state of its tiling layouts are saved too.
+
+File may be an absolute path, or a path relative to $XDG_DATA_HOME/wingo. If
+File is empty, the "session_file" option in options.wini is used.
+
+The path of the session file is returned.
+`
+}
+
+func (cmd SessionSave) Run() gribble.Value {
+	return syncRun(func() gribble.Value {
+		fpath, err := xclient.SessionFile(cmd.File)
+		if err != nil {
+			return cmdError("Could not save session: %s", err)
+		}
+		if err := xclient.SessionSave(fpath); err != nil {
+			wm.PopupError("Could not save session '%s': %s", fpath, err)
+			return cmdError("Could not save session '%s': %s", fpath, err)
+		}
+		return fpath
+	})
+}
+
+type SessionLoad struct {
+	File string `param:"1"`
+	Help string `
+Loads the session file specified by File. Windows that are already open and
+are in the session are moved back to where they were when the session was
+saved. Windows in the session that aren't open yet will be restored when they
+show up.
+
+File may be an absolute path, or a path relative to $XDG_DATA_HOME/wingo. If
+File is empty, the "session_file" option in options.wini is used.
+
+The path of the session file is returned.
+`
+}
+
+func (cmd SessionLoad) Run() gribble.Value {
+	return syncRun(func() gribble.Value {
+		fpath, err := xclient.SessionFile(cmd.File)
+		if err != nil {
+			return cmdError("Could not load session: %s", err)
+		}
+		if err := xclient.SessionLoad(fpath); err != nil {
+			wm.PopupError("Could not load session '%s': %s", fpath, err)
+			return cmdError("Could not load session '%s': %s", fpath, err)
+		}
+		return fpath
+	})
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/gribble"
+
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/workspace"
+)
+
+// withManual calls f with the Manual layout of the workspace specified, but
+// only if that workspace is currently tiling with the Manual layout.
+func withManual(wArg gribble.Any, f func(lay *layout.Manual)) {
+	withWorkspace(wArg, func(wrk *workspace.Workspace) {
+		if wrk.State != workspace.AutoTiling {
+			return
+		}
+		if lay, ok := wrk.LayoutAutoTiler().(*layout.Manual); ok {
+			f(lay)
+		}
+	})
+}
+
+type ManualSplit struct {
+	Workspace   gribble.Any `param:"1" types:"int,string"`
+	Orientation string      `param:"2"`
+	Help        string      `
+Puts the focused window in a new split in the Manual layout of the workspace
+specified by Workspace, so that the next window is placed next to it.
+
+Orientation may be "horizontal" (the next window goes to the right of the
+focused window) or "vertical" (the next window goes below the focused window).
+If the focused window is the only window in its split, the orientation of that
+split is changed instead.
+
+This command has no effect unless the workspace is using the Manual layout.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd ManualSplit) Run() gribble.Value {
+	var horizontal bool
+	switch strings.ToLower(cmd.Orientation) {
+	case "horizontal":
+		horizontal = true
+	case "vertical":
+		horizontal = false
+	default:
+		return cmdError("Unknown orientation '%s'. Expected 'horizontal' "+
+			"or 'vertical'.", cmd.Orientation)
+	}
+	return syncRun(func() gribble.Value {
+		withManual(cmd.Workspace, func(lay *layout.Manual) {
+			lay.Split(horizontal)
+		})
+		return nil
+	})
+}
+
+type ManualToggleSplit struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	Help      string      `
+Switches the orientation of the split containing the focused window between
+horizontal and vertical in the Manual layout of the workspace specified by
+Workspace.
+
+This command has no effect unless the workspace is using the Manual layout.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd ManualToggleSplit) Run() gribble.Value {
+	return syncRun(func() gribble.Value {
+		withManual(cmd.Workspace, func(lay *layout.Manual) {
+			lay.ToggleSplit()
+		})
+		return nil
+	})
+}
+
+type ManualMove struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	Direction string      `param:"2"`
+	Help      string      `
+Moves the focused window in the Manual layout of the workspace specified by
+Workspace. Direction may be one of "left", "right", "up" or "down".
+
+The window switches places with its neighbor in that direction. If there is no
+neighbor in its split, the window moves out of its split.
+
+This command has no effect unless the workspace is using the Manual layout.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd ManualMove) Run() gribble.Value {
+	dir, ok := layout.ParseDirection(cmd.Direction)
+	if !ok {
+		return cmdError("Unknown direction '%s'.", cmd.Direction)
+	}
+	return syncRun(func() gribble.Value {
+		withManual(cmd.Workspace, func(lay *layout.Manual) {
+			lay.MoveDirection(dir)
+		})
+		return nil
+	})
+}
+
+type ManualResize struct {
+	Workspace gribble.Any `param:"1" types:"int,string"`
+	Direction string      `param:"2"`
+	Amount    float64     `param:"3"`
+	Help      string      `
+Moves the edge of the focused window on the side given by Direction in the
+Manual layout of the workspace specified by Workspace. Direction may be one
+of "left", "right", "up" or "down".
+
+A positive Amount grows the window and a negative Amount shrinks it. Only the
+neighbor on the other side of the edge is resized to make room.
+
+Amount should be a ratio between -1.0 and 1.0.
+
+This command has no effect unless the workspace is using the Manual layout.
+
+Workspace may be a workspace index (integer) starting at 0, or a workspace
+name.
+`
+}
+
+func (cmd ManualResize) Run() gribble.Value {
+	dir, ok := layout.ParseDirection(cmd.Direction)
+	if !ok {
+		return cmdError("Unknown direction '%s'.", cmd.Direction)
+	}
+	return syncRun(func() gribble.Value {
+		withManual(cmd.Workspace, func(lay *layout.Manual) {
+			lay.ResizeEdge(dir, cmd.Amount)
+		})
+		return nil
+	})
+}
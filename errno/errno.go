@@ -7,4 +7,13 @@ var (
 
 	// 注册表驱动不可用
 	RegisterDriverNotValid = fmt.Errorf("Register driver not valid")
+
+	// 注册表 URL 的协议不支持
+	RegisterUnknownScheme = fmt.Errorf("Register URL scheme not supported")
+
+	// 注册表中找不到键
+	RegisterKeyNotFound = fmt.Errorf("Register key not found")
+
+	// 注册表值的类型不支持
+	RegisterUnknownType = fmt.Errorf("Register value type not supported")
 )
@@ -6,19 +6,82 @@ import (
 
 type Event interface{}
 
+// ClientInfo describes a client at the time an event was sent. It is embedded
+// in most client events, and its fields are sent as if they were fields of
+// the event itself.
+type ClientInfo struct {
+	Id        xproto.Window
+	Name      string
+	Class     string
+	Instance  string
+	Workspace string // empty if the client isn't on a workspace yet
+
+	X, Y, Width, Height int
+
+	Active     bool
+	Floating   bool
+	Maximized  bool
+	Fullscreen bool
+	Sticky     bool
+	Iconified  bool
+	Urgent     bool
+}
+
+// WorkspaceInfo describes a workspace at the time an event was sent. It is
+// embedded in workspace events in the same way as ClientInfo.
+type WorkspaceInfo struct {
+	Name   string
+	Head   int // -1 when the workspace isn't visible
+	Layout string
+	Active bool
+}
+
+// HeadInfo describes a physical head.
+type HeadInfo struct {
+	X, Y, Width, Height int
+	Workspace           string // the workspace visible on this head
+}
+
 type Noop struct{}
 
 type Restarting struct{}
 
 type Subscribed struct{}
 
+// Overflow is sent to a subscriber when events had to be dropped because
+// it wasn't reading them fast enough.
+type Overflow struct {
+	Dropped int
+}
+
+// Snapshot describes Wingo's current state. It is only sent to subscribers
+// that ask for it.
+type Snapshot struct {
+	Workspaces   []WorkspaceInfo
+	Clients      []ClientInfo
+	Heads        []HeadInfo
+	ActiveClient xproto.Window
+}
+
 type (
-	ChangedWorkspace        struct{}
-	ChangedVisibleWorkspace struct{}
-	ChangedWorkspaceNames   struct{}
+	// ChangedWorkspace is sent when the active workspace changes. The new
+	// active workspace is described.
+	ChangedWorkspace struct {
+		WorkspaceInfo
+	}
+
+	// ChangedVisibleWorkspace is sent when the set of visible workspaces
+	// changes. Visible is in the order of the heads they are on.
+	ChangedVisibleWorkspace struct {
+		Visible []WorkspaceInfo
+	}
+
+	ChangedWorkspaceNames struct {
+		Names []string
+	}
 
 	AddedWorkspace struct {
-		Name string
+		WorkspaceInfo
 	}
 
 	RemovedWorkspace struct {
@@ -28,35 +91,83 @@ type (
 
 type (
 	FocusedClient struct {
-		Id xproto.Window
+		ClientInfo
 	}
 	UnfocusedClient struct {
-		Id xproto.Window
+		ClientInfo
 	}
 	MappedClient struct {
-		Id xproto.Window
+		ClientInfo
 	}
 	UnmappedClient struct {
-		Id xproto.Window
+		ClientInfo
 	}
 	ManagedClient struct {
-		Id xproto.Window
+		ClientInfo
 	}
 	UnmanagedClient struct {
-		Id xproto.Window
-		Name string
-		Workspace string
-		Class string
-		Instance string
+		ClientInfo
 	}
 	ChangedClientName struct {
-		Id xproto.Window
+		ClientInfo
 	}
+
+	// ChangedActiveClient is sent when the focused client changes. When no
+	// client has focus, every field is zero.
 	ChangedActiveClient struct {
-		Id xproto.Window
+		ClientInfo
+	}
+
+	// ChangedClientGeometry is sent when a client is moved or resized.
+	// While a client is being dragged with the mouse, it is only sent once
+	// the drag is done.
+	ChangedClientGeometry struct {
+		ClientInfo
+	}
+
+	// UrgentClient and UnurgentClient are sent when a client starts and
+	// stops demanding attention.
+	UrgentClient struct {
+		ClientInfo
+	}
+	UnurgentClient struct {
+		ClientInfo
+	}
+
+	MaximizedClient struct {
+		ClientInfo
+	}
+	UnmaximizedClient struct {
+		ClientInfo
+	}
+	FullscreenedClient struct {
+		ClientInfo
+	}
+	UnfullscreenedClient struct {
+		ClientInfo
+	}
+	StuckClient struct {
+		ClientInfo
+	}
+	UnstuckClient struct {
+		ClientInfo
+	}
+	IconifiedClient struct {
+		ClientInfo
+	}
+	DeiconifiedClient struct {
+		ClientInfo
 	}
 )
 
 type ChangedLayout struct {
 	Workspace string
+	Layout    string
+	Head      int // -1 when the workspace isn't visible
+}
+
+// ChangedHeads is sent when physical heads are added, removed or change
+// geometry.
+type ChangedHeads struct {
+	Heads []HeadInfo
 }
@@ -1,8 +1,10 @@
 package event
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"reflect"
@@ -13,15 +15,42 @@ import (
 	"github.com/xuanmingyi/wingo/logger"
 )
 
+// replaySize is the number of events kept around for subscribers that ask
+// for a replay.
+const replaySize = 256
+
 var subs subscriptions
 
+// SnapshotFunc, when set, is used to build the Snapshot event sent to
+// subscribers that ask for one. It is set by the main package, since the
+// event package cannot see any of Wingo's state.
+var SnapshotFunc func() Snapshot
+
+// request is sent by a subscriber to change what it receives. Like events,
+// requests are JSON encoded and null terminated. Every field is optional.
+//
+// Subscribe is a list of event names (i.e., "FocusedClient") that the
+// subscriber wants to receive. An empty list means all events, which is the
+// default. Noop, Subscribed, Overflow, Restarting and Snapshot events are
+// always sent.
+//
+// Replay asks for up to that many of the most recent events (that pass the
+// subscriber's filter) to be sent again.
+//
+// Snapshot asks for a Snapshot event describing Wingo's current state.
+type request struct {
+	Subscribe []string
+	Replay    int
+	Snapshot  bool
+}
+
 func Notifier(X *xgbutil.XUtil, fp string) {
 	fp = fp + "-notify"
 	os.Remove(fp)
 
 	listener, err := net.Listen("unix", fp)
 	if err != nil {
-		logger.Error.Fatalln("Could not start IPC event listener: %s", err)
+		logger.Error.Fatalf("Could not start IPC event listener: %s", err)
 	}
 	defer listener.Close()
 
@@ -63,10 +92,22 @@ func handleSubscriber(conn net.Conn) {
 		return nil
 	}
 
+	// Requests are read in their own goroutine so that we can keep sending
+	// events while waiting for them. The channel is closed when the
+	// subscriber hangs up or sends garbage.
+	requests := make(chan request)
+	done := make(chan struct{})
+	defer close(done)
+	go readRequests(conn, id, requests, done)
+
 	if err := writeEvent(Subscribed{}); err != nil {
 		logger.Warning.Printf("Error sending initial subscription: %s", err)
 		return
 	}
+
+	// Events that were replayed may also be waiting in the events channel.
+	// Skip them so that they aren't sent twice.
+	replayed := 0
 	for {
 		select {
 		case <-time.After(5 * time.Second):
@@ -75,41 +116,180 @@ func handleSubscriber(conn net.Conn) {
 				return
 			}
 		case ev := <-events:
-			if err := writeEvent(ev); err != nil {
+			if ev.seq > 0 && ev.seq <= replayed {
+				continue
+			}
+			if err := writeEvent(ev.Event); err != nil {
 				logger.Warning.Printf("Error sending event: %s", err)
 				return
 			}
+		case req, ok := <-requests:
+			if !ok {
+				return
+			}
+			if req.Subscribe != nil {
+				subs.filter(id, req.Subscribe)
+			}
+			if req.Replay > 0 {
+				for _, ev := range subs.replay(id, req.Replay) {
+					if err := writeEvent(ev.Event); err != nil {
+						logger.Warning.Printf("Error replaying event: %s", err)
+						return
+					}
+					replayed = ev.seq
+				}
+			}
+			if req.Snapshot && SnapshotFunc != nil {
+				if err := writeEvent(SnapshotFunc()); err != nil {
+					logger.Warning.Printf("Error sending snapshot: %s", err)
+					return
+				}
+			}
+		}
+	}
+}
+
+// readRequests reads null terminated requests from a subscriber and sends
+// them on the given channel until the connection is closed or a request
+// cannot be decoded. It also stops once done is closed.
+func readRequests(conn net.Conn, id int, requests chan request,
+	done chan struct{}) {
+
+	defer close(requests)
+
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := reader.ReadString(0)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			logger.Warning.Printf("Error reading request from subscriber "+
+				"(id: %d): %s", id, err)
+			return
+		}
+		msg = msg[:len(msg)-1] // get rid of null terminator
+
+		var req request
+		if err := json.Unmarshal([]byte(msg), &req); err != nil {
+			logger.Warning.Printf("Could not decode request '%s' from "+
+				"subscriber (id: %d): %s", msg, id, err)
+			return
+		}
+		select {
+		case requests <- req:
+		case <-done:
+			return
 		}
 	}
 }
 
 // eventToMap converts an event struct into a map.
 // This is a terrible hack in order to inject the event name automatically.
+// The fields of embedded structs (i.e., ClientInfo) are flattened into the
+// event.
 func eventToMap(ev Event) map[string]interface{} {
 	rv := reflect.ValueOf(ev)
-	rt := rv.Type()
 	m := make(map[string]interface{})
 
-	m["EventName"] = rt.Name()
+	m["EventName"] = rv.Type().Name()
+	addFields(m, rv)
+	return m
+}
+
+func addFields(m map[string]interface{}, rv reflect.Value) {
+	rt := rv.Type()
 	nf := rv.NumField()
 	for i := 0; i < nf; i++ {
-		m[rt.Field(i).Name] = rv.Field(i).Interface()
+		field := rt.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addFields(m, rv.Field(i))
+			continue
+		}
+		m[field.Name] = rv.Field(i).Interface()
 	}
-	return m
+}
+
+// eventName returns the name of an event as it is sent to subscribers.
+func eventName(ev Event) string {
+	return reflect.TypeOf(ev).Name()
+}
+
+// alwaysSent returns true if ev should be sent regardless of a subscriber's
+// filter.
+func alwaysSent(ev Event) bool {
+	switch ev.(type) {
+	case Noop, Subscribed, Overflow, Restarting, Snapshot:
+		return true
+	}
+	return false
+}
+
+// seqEvent is an event along with its position in the stream of all events.
+// Synthetic events, like Overflow, have a zero seq.
+type seqEvent struct {
+	Event
+	seq int
 }
 
 type subscriptions struct {
-	add chan chan subscriber // sends info back on the given channel
-	remove chan int
-	notify chan Event
+	add     chan chan subscriber // sends info back on the given channel
+	remove  chan int
+	notify  chan Event
+	filters chan filterRequest
+	replays chan replayRequest
 }
 
 type subscriber struct {
-	id int
-	events chan Event
+	id     int
+	events chan seqEvent
+}
+
+type filterRequest struct {
+	id    int
+	names []string
+}
+
+type replayRequest struct {
+	id   int
+	n    int
+	recv chan []seqEvent
+}
+
+// subscriberState is what the subscription manager keeps track of for
+// every subscriber.
+type subscriberState struct {
+	events  chan seqEvent
+	filter  map[string]bool // empty means everything
+	dropped int
 }
 
-func (ss subscriptions) subscribe() (int, chan Event) {
+func (state *subscriberState) wants(ev Event) bool {
+	return len(state.filter) == 0 || alwaysSent(ev) ||
+		state.filter[eventName(ev)]
+}
+
+// send does a non-blocking send so that we drop notifications when the
+// client gets too busy (or fails). The number of dropped events is reported
+// with an Overflow event as soon as there is room for it.
+func (state *subscriberState) send(ev seqEvent) {
+	if state.dropped > 0 {
+		select {
+		case state.events <- seqEvent{Overflow{state.dropped}, 0}:
+			state.dropped = 0
+		default:
+			state.dropped++
+			return
+		}
+	}
+	select {
+	case state.events <- ev:
+	default:
+		state.dropped++
+	}
+}
+
+func (ss subscriptions) subscribe() (int, chan seqEvent) {
 	recv := make(chan subscriber)
 	ss.add <- recv
 	scriber := <-recv
@@ -120,33 +300,78 @@ func (ss subscriptions) unsubscribe(id int) {
 	ss.remove <- id
 }
 
+func (ss subscriptions) filter(id int, names []string) {
+	ss.filters <- filterRequest{id, names}
+}
+
+// replay returns up to n of the most recent events that pass the filter of
+// the given subscriber, oldest first.
+func (ss subscriptions) replay(id, n int) []seqEvent {
+	recv := make(chan []seqEvent)
+	ss.replays <- replayRequest{id, n, recv}
+	return <-recv
+}
+
 func manageSubscriptions() subscriptions {
 	nextId := int(1)
-	subscribed := make(map[int]chan Event)
+	subscribed := make(map[int]*subscriberState)
 	script := subscriptions{
 		make(chan chan subscriber),
 		make(chan int),
 		make(chan Event),
+		make(chan filterRequest),
+		make(chan replayRequest),
 	}
 
+	// history is a ring buffer of the last replaySize events.
+	history := make([]seqEvent, 0, replaySize)
+	nextSeq := 1
+
 	go func() {
 		for {
 			select {
 			case recv := <-script.add:
-				subscribed[nextId] = make(chan Event, 100)
-				recv <- subscriber{nextId, subscribed[nextId]}
+				subscribed[nextId] = &subscriberState{
+					events: make(chan seqEvent, 100),
+					filter: make(map[string]bool),
+				}
+				recv <- subscriber{nextId, subscribed[nextId].events}
 				nextId++
 			case id := <-script.remove:
-				close(subscribed[id])
+				close(subscribed[id].events)
 				delete(subscribed, id)
 				logger.Message.Printf("Subscriber disconnected (id: %d).", id)
+			case req := <-script.filters:
+				filter := make(map[string]bool)
+				for _, name := range req.names {
+					filter[name] = true
+				}
+				subscribed[req.id].filter = filter
+			case req := <-script.replays:
+				state := subscribed[req.id]
+				evs := make([]seqEvent, 0, req.n)
+				for i := len(history) - 1; i >= 0 && len(evs) < req.n; i-- {
+					if state.wants(history[i].Event) {
+						evs = append(evs, history[i])
+					}
+				}
+				for i, j := 0, len(evs)-1; i < j; i, j = i+1, j-1 {
+					evs[i], evs[j] = evs[j], evs[i]
+				}
+				req.recv <- evs
 			case ev := <-script.notify:
-				for _, subscriber := range subscribed {
-					// Do a non-blocking send so that we drop notifications
-					// when the client gets too busy (or fails).
-					select {
-					case subscriber <- ev:
-					default:
+				sev := seqEvent{ev, nextSeq}
+				nextSeq++
+
+				if len(history) == replaySize {
+					copy(history, history[1:])
+					history = history[:replaySize-1]
+				}
+				history = append(history, sev)
+
+				for _, state := range subscribed {
+					if state.wants(ev) {
+						state.send(sev)
 					}
 				}
 			}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/BurntSushi/gribble"
+
+	"github.com/xuanmingyi/wingo/commands"
+	"github.com/xuanmingyi/wingo/event"
+	"github.com/xuanmingyi/wingo/wm"
+	"github.com/xuanmingyi/wingo/xclient"
+)
+
+// eventSnapshot builds a Snapshot event for event subscribers that ask for
+// one. It is safe to call from any goroutine.
+func eventSnapshot() event.Snapshot {
+	var snap event.Snapshot
+	commands.SyncRun(func() gribble.Value {
+		for _, wrk := range wm.Heads.Workspaces.Wrks {
+			snap.Workspaces = append(snap.Workspaces, wrk.EventInfo())
+		}
+		for _, client := range wm.Clients {
+			c := client.(*xclient.Client)
+			snap.Clients = append(snap.Clients, c.EventInfo())
+		}
+		snap.Heads = wm.Heads.EventInfo()
+		if focused := wm.LastFocused(); focused != nil {
+			snap.ActiveClient = focused.Id()
+		}
+		return nil
+	})
+	return snap
+}
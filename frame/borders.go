@@ -25,25 +25,50 @@ func NewBorders(X *xgbutil.XUtil,
 	}
 
 	bf := &Borders{frame: f, theme: t}
-
-	bf.topSide = bf.newTopSide()
-	bf.bottomSide = bf.newBottomSide()
-	bf.leftSide = bf.newLeftSide()
-	bf.rightSide = bf.newRightSide()
-
-	bf.topLeft = bf.newTopLeft()
-	bf.topRight = bf.newTopRight()
-	bf.bottomLeft = bf.newBottomLeft()
-	bf.bottomRight = bf.newBottomRight()
+	bf.createPieces()
 
 	return bf, nil
 }
 
+// createPieces creates every window of the frame using its current theme.
+func (f *Borders) createPieces() {
+	f.topSide = f.newTopSide()
+	f.bottomSide = f.newBottomSide()
+	f.leftSide = f.newLeftSide()
+	f.rightSide = f.newRightSide()
+
+	f.topLeft = f.newTopLeft()
+	f.topRight = f.newTopRight()
+	f.bottomLeft = f.newBottomLeft()
+	f.bottomRight = f.newBottomRight()
+}
+
 func (f *Borders) Current() bool {
 	return f.client.Frame() == f
 }
 
 func (f *Borders) Destroy() {
+	f.destroyPieces()
+	f.frame.Destroy()
+}
+
+// SetTheme replaces the theme of this frame. Every window of the frame is
+// re-created, and the frame is redrawn if it's the current frame.
+func (f *Borders) SetTheme(t *BordersTheme) {
+	current := f.Current()
+	if current {
+		f.Off()
+	}
+	f.destroyPieces()
+	f.theme = t
+	f.createPieces()
+	if current {
+		f.On()
+	}
+}
+
+// destroyPieces destroys every window of the frame except for the parent.
+func (f *Borders) destroyPieces() {
 	f.topSide.Destroy()
 	f.bottomSide.Destroy()
 	f.leftSide.Destroy()
@@ -53,8 +78,6 @@ func (f *Borders) Destroy() {
 	f.topRight.Destroy()
 	f.bottomLeft.Destroy()
 	f.bottomRight.Destroy()
-
-	f.frame.Destroy()
 }
 
 func (f *Borders) Off() {
@@ -36,31 +36,35 @@ func NewFull(X *xgbutil.XUtil,
 	}
 
 	ff := &Full{frame: f, theme: t}
+	ff.createPieces()
 
-	ff.titleBar = ff.newTitleBar()
-	ff.titleText = ff.newTitleText()
-	ff.buttonClose = ff.newButtonClose()
-	ff.buttonMaximize = ff.newButtonMaximize()
-	ff.buttonMinimize = ff.newButtonMinimize()
-	ff.icon = ff.newIcon()
-
-	if ff.theme.BorderSize > 0 {
-		ff.topSide = ff.newTopSide()
-		ff.bottomSide = ff.newBottomSide()
-		ff.leftSide = ff.newLeftSide()
-		ff.rightSide = ff.newRightSide()
-		ff.titleBottom = ff.newTitleBottom()
-
-		ff.topLeft = ff.newTopLeft()
-		ff.topRight = ff.newTopRight()
-		ff.bottomLeft = ff.newBottomLeft()
-		ff.bottomRight = ff.newBottomRight()
-	}
+	return ff, nil
+}
 
-	ff.UpdateTitle()
-	ff.UpdateIcon()
+// createPieces creates every window of the frame using its current theme.
+func (f *Full) createPieces() {
+	f.titleBar = f.newTitleBar()
+	f.titleText = f.newTitleText()
+	f.buttonClose = f.newButtonClose()
+	f.buttonMaximize = f.newButtonMaximize()
+	f.buttonMinimize = f.newButtonMinimize()
+	f.icon = f.newIcon()
 
-	return ff, nil
+	if f.theme.BorderSize > 0 {
+		f.topSide = f.newTopSide()
+		f.bottomSide = f.newBottomSide()
+		f.leftSide = f.newLeftSide()
+		f.rightSide = f.newRightSide()
+		f.titleBottom = f.newTitleBottom()
+
+		f.topLeft = f.newTopLeft()
+		f.topRight = f.newTopRight()
+		f.bottomLeft = f.newBottomLeft()
+		f.bottomRight = f.newBottomRight()
+	}
+
+	f.UpdateTitle()
+	f.UpdateIcon()
 }
 
 func (f *Full) Current() bool {
@@ -68,6 +72,30 @@ func (f *Full) Current() bool {
 }
 
 func (f *Full) Destroy() {
+	f.destroyPieces()
+	f.frame.Destroy()
+}
+
+// SetTheme replaces the theme of this frame. Every window of the frame is
+// re-created, and the frame is redrawn if it's the current frame.
+func (f *Full) SetTheme(t *FullTheme) {
+	current := f.Current()
+	if current {
+		f.Off()
+	}
+	f.destroyPieces()
+	f.theme = t
+	f.createPieces()
+	if f.client.IsMaximized() {
+		f.Maximize()
+	}
+	if current {
+		f.On()
+	}
+}
+
+// destroyPieces destroys every window of the frame except for the parent.
+func (f *Full) destroyPieces() {
 	if f.theme.BorderSize > 0 {
 		f.topSide.Destroy()
 		f.bottomSide.Destroy()
@@ -87,8 +115,6 @@ func (f *Full) Destroy() {
 	f.buttonClose.Destroy()
 	f.buttonMaximize.Destroy()
 	f.buttonMinimize.Destroy()
-
-	f.frame.Destroy()
 }
 
 func (f *Full) Off() {
@@ -27,6 +27,15 @@ func (f *Slim) Current() bool {
 	return f.client.Frame() == f
 }
 
+// SetTheme replaces the theme of this frame. The frame is redrawn if it's the
+// current frame.
+func (f *Slim) SetTheme(t *SlimTheme) {
+	f.theme = t
+	if f.Current() {
+		f.On()
+	}
+}
+
 func (f *Slim) Off() {}
 
 func (f *Slim) On() {
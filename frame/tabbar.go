@@ -0,0 +1,160 @@
+package frame
+
+import (
+	"image"
+
+	"github.com/BurntSushi/xgb/xproto"
+
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xgraphics"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/render"
+)
+
+// Tab is what is shown in a single tab of a TabBar.
+type Tab struct {
+	Name string
+	Icon *xgraphics.Image
+}
+
+// TabBar is a row of tabs that isn't attached to any particular client. It
+// is drawn by tabbed layouts above their clients, with one tab for each
+// client. The tabs look like the title bar of a Full frame, and the tab of
+// the active client uses the active title colors.
+type TabBar struct {
+	X       *xgbutil.XUtil
+	theme   *FullTheme
+	win     *xwindow.Window
+	tabs    []*piece
+	clicked func(i int)
+}
+
+// NewTabBar creates a new tab bar. It isn't shown until Update is called.
+// When the tab at index i is clicked, clicked is called with i.
+func NewTabBar(X *xgbutil.XUtil, theme *FullTheme,
+	clicked func(i int)) *TabBar {
+
+	tb := &TabBar{
+		X:       X,
+		theme:   theme,
+		tabs:    make([]*piece, 0),
+		clicked: clicked,
+	}
+	tb.win = xwindow.Must(xwindow.Create(X, X.RootWin()))
+	tb.win.Change(xproto.CwOverrideRedirect, 1)
+	tb.win.Change(xproto.CwBackPixel, uint32(theme.IBorderColor.Int()))
+	return tb
+}
+
+// Height returns the height of the tab bar in the current theme.
+func (tb *TabBar) Height() int {
+	return tb.theme.TitleSize
+}
+
+// SetTheme changes the theme used the next time the tabs are drawn.
+func (tb *TabBar) SetTheme(theme *FullTheme) {
+	tb.theme = theme
+	tb.win.Change(xproto.CwBackPixel, uint32(theme.IBorderColor.Int()))
+}
+
+// Update moves the tab bar to geom (its height is always the height of the
+// title bar in the theme) and draws one tab for each of tabs. The tab at
+// index active is drawn as active.
+func (tb *TabBar) Update(geom xrect.Rect, tabs []Tab, active int) {
+	if len(tabs) == 0 {
+		tb.Hide()
+		return
+	}
+	for len(tb.tabs) < len(tabs) {
+		tb.tabs = append(tb.tabs, tb.newTab(len(tb.tabs)))
+	}
+	for len(tb.tabs) > len(tabs) {
+		tb.tabs[len(tb.tabs)-1].Destroy()
+		tb.tabs = tb.tabs[:len(tb.tabs)-1]
+	}
+
+	// Every tab gets the same width, with a pixel in between each of them.
+	// Any pixels left over from rounding go to the last tab.
+	x, y, w, h := geom.X(), geom.Y(), geom.Width(), tb.theme.TitleSize
+	each := (w - (len(tabs) - 1)) / len(tabs)
+	for i, tab := range tabs {
+		tabw := each
+		if i == len(tabs)-1 {
+			tabw = w - (each+1)*(len(tabs)-1)
+		}
+		if tabw < 1 {
+			tabw = 1
+		}
+
+		p := tb.tabs[i]
+		p.Create(tb.tabImage(tab, tabw, true), tb.tabImage(tab, tabw, false))
+		p.MoveResize(i*(each+1), 0, tabw, h)
+		p.Map()
+		if i == active {
+			p.Active()
+		} else {
+			p.Inactive()
+		}
+	}
+
+	tb.win.MoveResize(x, y, w, h)
+	tb.win.Map()
+}
+
+// Hide unmaps the tab bar.
+func (tb *TabBar) Hide() {
+	tb.win.Unmap()
+}
+
+// Destroy destroys the tab bar and all of its tabs.
+func (tb *TabBar) Destroy() {
+	for _, p := range tb.tabs {
+		p.Destroy()
+	}
+	tb.tabs = nil
+	tb.win.Destroy()
+}
+
+func (tb *TabBar) newTab(i int) *piece {
+	win := xwindow.Must(xwindow.Create(tb.X, tb.win.Id))
+	win.Listen(xproto.EventMaskButtonPress)
+	xevent.ButtonPressFun(
+		func(X *xgbutil.XUtil, ev xevent.ButtonPressEvent) {
+			tb.clicked(i)
+		}).Connect(tb.X, win.Id)
+	return newPiece(win, nil, nil)
+}
+
+// tabImage draws the icon and name of a tab using the title bar colors of
+// the theme.
+func (tb *TabBar) tabImage(tab Tab, width int, active bool) *xgraphics.Image {
+	size := tb.theme.TitleSize
+	titleClr, fontClr := tb.theme.ITitleColor, tb.theme.IFontColor
+	if active {
+		titleClr, fontClr = tb.theme.ATitleColor, tb.theme.AFontColor
+	}
+
+	img := render.NewBorder(tb.X, 0, render.NoColor, titleClr,
+		width, size, render.GradientVert, render.GradientRegular)
+
+	textx := 2
+	if tab.Icon != nil && width > size {
+		sub := image.Rect(2, 2, size-2, size-2)
+		xgraphics.Blend(img.SubImage(sub).(*xgraphics.Image), tab.Icon,
+			image.ZP)
+		textx = size
+	}
+
+	_, eh := xgraphics.Extents(tb.theme.Font, tb.theme.FontSize, tab.Name)
+	_, _, err := img.Text(textx, (size-eh)/2, fontClr.ImageColor(),
+		tb.theme.FontSize, tb.theme.Font, tab.Name)
+	if err != nil {
+		logger.Warning.Printf("Could not draw tab for '%s' because: %v",
+			tab.Name, err)
+	}
+	return img.Image
+}
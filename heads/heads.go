@@ -9,6 +9,9 @@ import (
 	"github.com/BurntSushi/xgbutil/xrect"
 	"github.com/BurntSushi/xgbutil/xwindow"
 
+	"github.com/xuanmingyi/wingo/event"
+	"github.com/xuanmingyi/wingo/hook"
+	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/workspace"
 )
@@ -23,12 +26,15 @@ type Heads struct {
 	visibles   []*workspace.Workspace // Slice of all visible workspaces.
 }
 
-func NewHeads(X *xgbutil.XUtil, defaultLayout string) *Heads {
+func NewHeads(X *xgbutil.XUtil, defaultLayout string,
+	defaultGaps layout.Gaps, newTabBar func() layout.TabBar) *Heads {
+
 	hds := &Heads{
 		X:      X,
 		active: 0,
 	}
-	hds.Workspaces = workspace.NewWorkspaces(X, hds, defaultLayout)
+	hds.Workspaces = workspace.NewWorkspaces(
+		X, hds, defaultLayout, defaultGaps, newTabBar)
 	return hds
 }
 
@@ -161,6 +167,28 @@ func (hds *Heads) Reload(clients Clients) {
 			wrk.Hide()
 		}
 	}
+
+	event.Notify(event.ChangedHeads{Heads: hds.EventInfo()})
+	hook.Fire(hook.HeadChanged, hook.Args{
+		Workspace: hds.visibles[hds.active].Name,
+		Head:      fmt.Sprintf("%d", hds.active),
+	})
+}
+
+// EventInfo describes every head for event subscribers, in the same order
+// as the visible workspaces.
+func (hds *Heads) EventInfo() []event.HeadInfo {
+	infos := make([]event.HeadInfo, len(hds.geom))
+	for i, hd := range hds.geom {
+		infos[i] = event.HeadInfo{
+			X:         hd.X(),
+			Y:         hd.Y(),
+			Width:     hd.Width(),
+			Height:    hd.Height(),
+			Workspace: hds.visibles[i].Name,
+		}
+	}
+	return infos
 }
 
 func (hds *Heads) ApplyStruts(clients Clients) {
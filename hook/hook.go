@@ -2,6 +2,8 @@ package hook
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/gribble"
@@ -14,31 +16,64 @@ import (
 // Wingo. When that action happens, every hook in the corresponding group
 // is fired.
 const (
-	Startup   Type = "startup"
-	Restarted Type = "restart"
-	Managed   Type = "managed"
-	Focused   Type = "focused"
-	Unfocused Type = "unfocused"
+	Startup          Type = "startup"
+	Restarted        Type = "restart"
+	Managed          Type = "managed"
+	Unmanaged        Type = "unmanaged"
+	Focused          Type = "focused"
+	Unfocused        Type = "unfocused"
+	Urgent           Type = "urgent"
+	NameChanged      Type = "name_changed"
+	WorkspaceChanged Type = "workspace_changed"
+	LayoutChanged    Type = "layout_changed"
+	HeadChanged      Type = "head_changed"
 )
 
 var (
 	// A global corresponding to the Gribble execution environment.
 	gribbleEnv *gribble.Environment
 
+	// Runs a function on the main X event loop and blocks until it's done.
+	// Used to run synchronous hooks.
+	runSync func(f func())
+
 	// A map from group constants to group values.
-	groups = map[Type]group{
-		Startup:   make(group, 0),
-		Restarted: make(group, 0),
-		Managed:   make(group, 0),
-		Focused:   make(group, 0),
-		Unfocused: make(group, 0),
-	}
+	groups = newGroups()
 )
 
 type Type string
 
+// newGroups returns a map from every group constant to an empty group.
+func newGroups() map[Type]group {
+	return map[Type]group{
+		Startup:          make(group, 0),
+		Restarted:        make(group, 0),
+		Managed:          make(group, 0),
+		Unmanaged:        make(group, 0),
+		Focused:          make(group, 0),
+		Unfocused:        make(group, 0),
+		Urgent:           make(group, 0),
+		NameChanged:      make(group, 0),
+		WorkspaceChanged: make(group, 0),
+		LayoutChanged:    make(group, 0),
+		HeadChanged:      make(group, 0),
+	}
+}
+
 type group []hook
 
+func (grp group) Len() int {
+	return len(grp)
+}
+
+func (grp group) Less(i, j int) bool {
+	return grp[i].priority < grp[j].priority
+}
+
+func (grp group) Swap(i, j int) {
+	grp[i], grp[j] = grp[j], grp[i]
+}
+
 type hook struct {
 	// From the config file. Nice for error messages.
 	name string
@@ -56,25 +91,57 @@ type hook struct {
 	// are fired if '(and satisfies[0] satisfies[1] ... satisfies[n-1])' is
 	// satisfied.
 	consequences []string
+
+	// When true, the hook is run on the main X event loop, so that its
+	// consequences are done before Wingo moves on. (i.e., before a newly
+	// managed client is mapped.)
+	sync bool
+
+	// When true and this hook matches, no hooks after it in the same group
+	// are run.
+	stop bool
+
+	// Hooks in a group are run in increasing order of priority. Hooks with
+	// the same priority are run in the order they're defined.
+	priority int
 }
 
-// Initializes the hooks package with a Gribble execution environment and
-// a file path to a wini formatted hooks configuration file. If the
-// initialization fails, only a warning is logged since hooks are not
-// essential for Wingo to run.
-func Initialize(env *gribble.Environment, fpath string) {
+// Initializes the hooks package with a Gribble execution environment, a
+// function that runs synchronous hooks on the main X event loop and a file
+// path to a wini formatted hooks configuration file. If the initialization
+// fails, only a warning is logged since hooks are not essential for Wingo to
+// run.
+func Initialize(env *gribble.Environment, sync func(f func()), fpath string) {
 	gribbleEnv = env
+	runSync = sync
 
+	if err := Reload(fpath); err != nil {
+		logger.Warning.Printf("Could not parse '%s': %s", fpath, err)
+	}
+}
+
+// Reload replaces every hook with those read from a wini formatted hooks
+// configuration file. If the file can't be parsed, an error is returned and
+// the current hooks are kept. Hooks that can't be loaded are skipped with a
+// warning.
+//
+// Reload must be called from the main X event loop.
+func Reload(fpath string) error {
 	cdata, err := wini.Parse(fpath)
 	if err != nil {
-		logger.Warning.Printf("Could not parse '%s': %s", fpath, err)
-		return
+		return err
 	}
+	grps := newGroups()
 	for _, hookName := range cdata.Sections() {
-		if err := readSection(cdata, hookName); err != nil {
+		if err := readSection(grps, cdata, hookName); err != nil {
 			logger.Warning.Printf("Could not load hook '%s': %s", hookName, err)
 		}
 	}
+	for _, grp := range grps {
+		sort.Stable(grp)
+	}
+	groups = grps
+	return nil
 }
 
 // Fire will attempt to run every hook in the group specified, while replacing
@@ -85,72 +152,113 @@ func Initialize(env *gribble.Environment, fpath string) {
 // of the consequences. If any of the match conditions are false, we stop
 // and condinue on to the next hook.
 //
-// Note that Fire immediately return a channel, as it executes in its own
-// goroutine. The channel can be used to block until Fire has finished.
+// Hooks with the "sync" option are run first, on the main X event loop, and
+// Fire doesn't return until they're done. The rest of the hooks are run in
+// their own goroutine. If a hook with the "stop" option matches, no other
+// hooks after it are run. (A synchronous hook can stop asynchronous hooks,
+// but not the other way around.)
+//
+// Fire must be called from the main X event loop.
 func Fire(hk Type, args Args) {
-	go func() {
-		if _, ok := groups[hk]; !ok {
-			logger.Warning.Printf("Unknown hook group '%s'.", hk)
+	if _, ok := groups[hk]; !ok {
+		logger.Warning.Printf("Unknown hook group '%s'.", hk)
+		return
+	}
+
+	syncs, asyncs := make(group, 0), make(group, 0)
+	for _, hook := range groups[hk] {
+		if hook.sync {
+			syncs = append(syncs, hook)
+		} else {
+			asyncs = append(asyncs, hook)
+		}
+	}
+	if len(syncs) > 0 {
+		stopped := false
+		runSync(func() {
+			stopped = syncs.run(hk, args)
+		})
+		if stopped {
 			return
 		}
-		for _, hook := range groups[hk] {
-			// Run all of the match conditions. Depending upon the value
-			// of hk.conjunction, we treat the conditions as either a set
-			// of conjunctions or a set of disjunctions.
-			andMatched := true
-			orMatched := false
-			for _, condCmd := range hook.satisfies {
-				val, err := gribbleEnv.Run(args.apply(condCmd))
-				if err != nil {
-					logger.Warning.Printf("When executing the 'match' "+
-						"conditions for your '%s' hook in the '%s' group, "+
-						"the command '%s' returned an error: %s",
-						hook.name, hk, condCmd, err)
-					andMatched = false
-					orMatched = false
-					break
-				}
-				if gribbleBool(val) {
-					logger.Lots.Printf("Condition '%s' matched "+
-						"for the hook '%s' in the '%s' group.",
-						condCmd, hook.name, hk)
-					orMatched = true
-					if !hook.conjunction {
-						break
-					}
-				} else {
-					logger.Lots.Printf("Condition '%s' failed to match "+
-						"for the hook '%s' in the '%s' group.",
-						condCmd, hook.name, hk)
-					andMatched = false
-					if hook.conjunction {
-						break
-					}
-				}
-			}
-			if hook.conjunction && !andMatched {
-				continue
+	}
+	if len(asyncs) > 0 {
+		go asyncs.run(hk, args)
+	}
+}
+
+// run runs every hook in the group in order, until a hook with the "stop"
+// option matches. It returns true if a hook stopped the group.
+func (grp group) run(hk Type, args Args) bool {
+	for _, hook := range grp {
+		if hook.run(hk, args) && hook.stop {
+			logger.Lots.Printf("The hook '%s' in the '%s' group has stopped "+
+				"the rest of the group.", hook.name, hk)
+			return true
+		}
+	}
+	return false
+}
+
+// run executes the match conditions of a hook and, if they match, all of its
+// consequences. It returns true if the hook matched.
+func (hook hook) run(hk Type, args Args) bool {
+	// Run all of the match conditions. Depending upon the value
+	// of hk.conjunction, we treat the conditions as either a set
+	// of conjunctions or a set of disjunctions.
+	andMatched := true
+	orMatched := false
+	for _, condCmd := range hook.satisfies {
+		val, err := gribbleEnv.Run(args.apply(condCmd))
+		if err != nil {
+			logger.Warning.Printf("When executing the 'match' "+
+				"conditions for your '%s' hook in the '%s' group, "+
+				"the command '%s' returned an error: %s",
+				hook.name, hk, condCmd, err)
+			andMatched = false
+			orMatched = false
+			break
+		}
+		if gribbleBool(val) {
+			logger.Lots.Printf("Condition '%s' matched "+
+				"for the hook '%s' in the '%s' group.",
+				condCmd, hook.name, hk)
+			orMatched = true
+			if !hook.conjunction {
+				break
 			}
-			if !hook.conjunction && !orMatched {
-				continue
+		} else {
+			logger.Lots.Printf("Condition '%s' failed to match "+
+				"for the hook '%s' in the '%s' group.",
+				condCmd, hook.name, hk)
+			andMatched = false
+			if hook.conjunction {
+				break
 			}
+		}
+	}
+	if hook.conjunction && !andMatched {
+		return false
+	}
+	if !hook.conjunction && !orMatched {
+		return false
+	}
 
-			logger.Lots.Printf("The hook '%s' in the '%s' group has matched!",
-				hook.name, hk)
-
-			// We have a match! Let's proceed to the consequences...
-			for _, consequentCmd := range hook.consequences {
-				_, err := gribbleEnv.Run(args.apply(consequentCmd))
-				if err != nil {
-					logger.Warning.Printf("When executing the consequences "+
-						"for your '%s' hook in the '%s' group, the command "+
-						"'%s' returned an error: %s",
-						hook.name, hk, consequentCmd, err)
-					// consequent commands are independent, so we march on.
-				}
-			}
+	logger.Lots.Printf("The hook '%s' in the '%s' group has matched!",
+		hook.name, hk)
+
+	// We have a match! Let's proceed to the consequences...
+	for _, consequentCmd := range hook.consequences {
+		_, err := gribbleEnv.Run(args.apply(consequentCmd))
+		if err != nil {
+			logger.Warning.Printf("When executing the consequences "+
+				"for your '%s' hook in the '%s' group, the command "+
+				"'%s' returned an error: %s",
+				hook.name, hk, consequentCmd, err)
+			// consequent commands are independent, so we march on.
 		}
-	}()
+	}
+	return true
 }
 
 // gribbleBool translates a value returned by a Gribble command to a boolean
@@ -164,9 +272,9 @@ func gribbleBool(val gribble.Any) bool {
 }
 
 // readSection loads a particular section from the configuration file into
-// the hook groups. One section may result in the same hook being added to
-// multiple groups.
-func readSection(cdata *wini.Data, section string) error {
+// the hook groups given. One section may result in the same hook being added
+// to multiple groups.
+func readSection(grps map[Type]group, cdata *wini.Data, section string) error {
 	// First lets roll up the match conditions.
 	match := cdata.GetKey(section, "match")
 	if match == nil {
@@ -193,16 +301,41 @@ func readSection(cdata *wini.Data, section string) error {
 		}
 	}
 
-	// Now traverse all of the keys in the section. We'll skip "match" since
-	// we've already grabbed the data. Any other key should correspond to
-	// a hook group name.
+	// The rest of the options are also optional.
+	sync, stop, priority := false, false, 0
+	if key := cdata.GetKey(section, "sync"); key != nil {
+		if vals, err := key.Bools(); err != nil {
+			logger.Warning.Println(err)
+		} else {
+			sync = vals[0]
+		}
+	}
+	if key := cdata.GetKey(section, "stop"); key != nil {
+		if vals, err := key.Bools(); err != nil {
+			logger.Warning.Println(err)
+		} else {
+			stop = vals[0]
+		}
+	}
+	if key := cdata.GetKey(section, "priority"); key != nil {
+		if vals, err := key.Ints(); err != nil {
+			logger.Warning.Println(err)
+		} else {
+			priority = vals[0]
+		}
+	}
+
+	// Now traverse all of the keys in the section. We'll skip the options
+	// since we've already grabbed their data. Any other key should correspond
+	// to a hook group name.
 	addedOne := false
 	for _, key := range cdata.Keys(section) {
 		groupName := Type(key.Name())
-		if groupName == "match" || groupName == "conjunction" {
+		switch groupName {
+		case "match", "conjunction", "sync", "stop", "priority":
 			continue
 		}
-		if _, ok := groups[groupName]; !ok {
+		if _, ok := grps[groupName]; !ok {
 			return fmt.Errorf("Unrecognized hook group '%s' in the '%s' hook.",
 				groupName, section)
 		}
@@ -220,8 +353,11 @@ func readSection(cdata *wini.Data, section string) error {
 			satisfies:    satisfies,
 			conjunction:  conjunction,
 			consequences: consequences,
+			sync:         sync,
+			stop:         stop,
+			priority:     priority,
 		}
-		groups[groupName] = append(groups[groupName], hook)
+		grps[groupName] = append(grps[groupName], hook)
 		addedOne = true
 	}
 	if !addedOne {
@@ -251,8 +387,13 @@ func checkCommands(cmds []string) (string, error) {
 //		Client: "identifier of window being focused",
 //	}
 //	hook.Fire(hook.Focused, args)
+//
+// Client and Head are substituted verbatim, since they are numbers.
+// Workspace is a name, so it is substituted as a quoted string.
 type Args struct {
-	Client string
+	Client    string
+	Workspace string
+	Head      string
 }
 
 // apply takes a command string and replaces special strings with values in
@@ -262,6 +403,13 @@ func (args Args) apply(cmd string) string {
 	if len(args.Client) > 0 {
 		replace = append(replace, []string{"\":client:\"", args.Client}...)
 	}
+	if len(args.Workspace) > 0 {
+		replace = append(replace,
+			[]string{"\":workspace:\"", strconv.Quote(args.Workspace)}...)
+	}
+	if len(args.Head) > 0 {
+		replace = append(replace, []string{"\":head:\"", args.Head}...)
+	}
 
 	if len(replace) == 0 {
 		return cmd
@@ -21,6 +21,9 @@ import (
 //
 // Note that every message between the server and client MUST be null
 // terminated.
+//
+// Messages starting with '{' are JSON requests instead of plain Gribble
+// commands, and get a JSON response. See rpcRequest.
 func ipc(X *xgbutil.XUtil) {
 	fpath := socketFilePath(X)
 
@@ -80,6 +83,11 @@ func handleClient(conn net.Conn) {
 		}
 		msg = msg[:len(msg)-1] // get rid of null terminator
 
+		if isRPC(msg) {
+			fmt.Fprintf(conn, "%s%c", handleRPC(msg), 0)
+			continue
+		}
+
 		logger.Lots.Printf("Running command from IPC: '%s'.", msg)
 
 		// Run the command. We set the error reporting to verbose. Be kind!
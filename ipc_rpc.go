@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuanmingyi/wingo/commands"
+	"github.com/xuanmingyi/wingo/logger"
+)
+
+// rpcRequest is a structured command sent over the IPC socket. Any message
+// on the command socket that starts with '{' is decoded as an rpcRequest
+// instead of a plain Gribble command. Like every other message, it must be
+// null terminated.
+//
+// If Args is empty, Command may be any Gribble command (or series of
+// commands), i.e., `{"command": "GetClientName (GetActive)"}`. Otherwise,
+// Command must be the name of a single command, and Args are its parameters.
+// Each argument must be a JSON number or string.
+type rpcRequest struct {
+	Id      json.RawMessage `json:"id"`
+	Command string          `json:"command"`
+	Args    []interface{}   `json:"args"`
+}
+
+// rpcResponse is sent back for every rpcRequest. Id is copied verbatim from
+// the request. Type is one of "int", "float", "string" or "nil", and says how
+// Result should be interpreted. When Error is not empty, Result is nil.
+//
+// Error contains both Gribble errors (i.e., an unknown command or a bad
+// parameter type) and errors reported by Wingo commands. (Which are strings
+// starting with "ERROR: " in the plain text protocol.)
+type rpcResponse struct {
+	Id     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  string          `json:"error,omitempty"`
+	Type   string          `json:"type"`
+}
+
+// isRPC returns true if msg should be handled as an rpcRequest.
+func isRPC(msg string) bool {
+	return strings.HasPrefix(strings.TrimSpace(msg), "{")
+}
+
+// handleRPC decodes a request, runs it and returns the encoded response.
+// The returned slice is not null terminated.
+func handleRPC(msg string) []byte {
+	var req rpcRequest
+	var resp rpcResponse
+
+	if err := json.Unmarshal([]byte(msg), &req); err != nil {
+		resp = rpcError(nil, "Could not decode request: %s", err)
+	} else {
+		resp = runRPC(req)
+	}
+
+	bs, err := json.Marshal(resp)
+	if err != nil {
+		// This should never happen, since all values are basic types.
+		logger.Warning.Printf("Could not encode IPC response: %s", err)
+		bs, _ = json.Marshal(rpcError(req.Id, "%s", err))
+	}
+	return bs
+}
+
+func runRPC(req rpcRequest) rpcResponse {
+	cmd, err := rpcCommand(req)
+	if err != nil {
+		return rpcError(req.Id, "%s", err)
+	}
+	logger.Lots.Printf("Running command from IPC (JSON): '%s'.", cmd)
+
+	commands.Env.Verbose = true
+	val, err := commands.Env.RunMany(cmd)
+	commands.Env.Verbose = false
+	if err != nil {
+		logger.Lots.Printf("ERROR running command: '%s'.", err)
+		return rpcError(req.Id, "%s", err)
+	}
+
+	resp := rpcResponse{Id: req.Id, Result: val}
+	switch v := val.(type) {
+	case nil:
+		resp.Type = "nil"
+	case int:
+		resp.Type = "int"
+	case float64:
+		resp.Type = "float"
+	case string:
+		if strings.HasPrefix(v, "ERROR: ") {
+			return rpcError(req.Id, "%s", v[len("ERROR: "):])
+		}
+		resp.Type = "string"
+	default:
+		logger.Error.Fatalf("BUG: Unknown Gribble return type: %T", v)
+	}
+	return resp
+}
+
+// rpcCommand builds a Gribble command string from a request.
+func rpcCommand(req rpcRequest) (string, error) {
+	name := strings.TrimSpace(req.Command)
+	if len(name) == 0 {
+		return "", fmt.Errorf("No command given.")
+	}
+	if len(req.Args) == 0 {
+		return name, nil
+	}
+	if strings.ContainsAny(name, " \t\n()") {
+		return "", fmt.Errorf("When arguments are given, the command must "+
+			"be a single command name, but got '%s'.", name)
+	}
+
+	pieces := []string{name}
+	for i, arg := range req.Args {
+		switch v := arg.(type) {
+		case float64:
+			// JSON doesn't distinguish between integers and floats, so
+			// the shortest representation without an exponent is used:
+			// integral numbers (like window ids) are sent as integers.
+			// Gribble will not convert them back to floats.
+			pieces = append(pieces, strconv.FormatFloat(v, 'f', -1, 64))
+		case string:
+			s, err := rpcQuote(v)
+			if err != nil {
+				return "", fmt.Errorf("Argument %d: %s", i+1, err)
+			}
+			pieces = append(pieces, s)
+		default:
+			return "", fmt.Errorf("Argument %d has unsupported type %T. "+
+				"Only numbers and strings are allowed.", i+1, arg)
+		}
+	}
+	return strings.Join(pieces, " "), nil
+}
+
+// rpcQuote quotes a string so that Gribble parses it back verbatim. Gribble
+// does not support escape sequences, so a string containing both a double
+// quote and a back quote cannot be represented.
+func rpcQuote(s string) (string, error) {
+	switch {
+	case !strings.Contains(s, "\"") && !strings.Contains(s, "\n"):
+		return "\"" + s + "\"", nil
+	case !strings.Contains(s, "`"):
+		return "`" + s + "`", nil
+	}
+	return "", fmt.Errorf("Strings cannot contain both '\"' and '`'.")
+}
+
+func rpcError(id json.RawMessage, format string, v ...interface{}) rpcResponse {
+	return rpcResponse{
+		Id:     id,
+		Result: nil,
+		Error:  fmt.Sprintf(format, v...),
+		Type:   "nil",
+	}
+}
@@ -0,0 +1,16 @@
+/*
+package launcher indexes the applications that can be started from Wingo's
+application launcher prompt, and remembers which of them are used most.
+
+There are two kinds of entries: applications read from the XDG desktop
+entries (the .desktop files in the "applications" directory of each XDG data
+directory), and commands, which are the executables found in $PATH.
+
+Every time an entry is launched, it is added to a History. A History orders
+entries by "frecency": entries that are launched often and that were launched
+recently come first. Histories are saved as JSON.
+
+This package only knows how to find, order and store entries. Showing them
+in a prompt is done in the wm package.
+*/
+package launcher
@@ -0,0 +1,229 @@
+package launcher
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Entry is a single application or command that can be launched.
+type Entry struct {
+	// ID identifies the entry in a History. It is the desktop file ID (e.g.,
+	// "firefox.desktop") of an application, and the name of the executable
+	// of a command.
+	ID string
+
+	// Name is what is shown in the prompt. For a command, it's the name of
+	// the executable.
+	Name string
+
+	// Exec is the shell command that launches the entry. The field codes
+	// (like %f or %U) of a desktop entry have been removed.
+	Exec string
+
+	// Icon is the icon name or absolute icon path of an application. It is
+	// always empty for a command.
+	Icon       string
+	Categories []string
+
+	// Terminal is true when the application must be run in a terminal.
+	Terminal bool
+
+	// Desktop is true when the entry was read from a desktop entry.
+	Desktop bool
+}
+
+// Index returns every application found in the XDG data directories,
+// followed by every command found in $PATH.
+func Index() []*Entry {
+	var dirs []string
+	for _, dir := range DataDirs() {
+		dirs = append(dirs, path.Join(dir, "applications"))
+	}
+	return append(Applications(dirs), Commands(os.Getenv("PATH"))...)
+}
+
+// DataDirs returns the XDG data directories in order of preference:
+// $XDG_DATA_HOME followed by every directory in $XDG_DATA_DIRS. The usual
+// defaults are used when those aren't set.
+func DataDirs() []string {
+	home := os.Getenv("XDG_DATA_HOME")
+	if len(home) == 0 {
+		home = path.Join(os.Getenv("HOME"), ".local", "share")
+	}
+	dirs := os.Getenv("XDG_DATA_DIRS")
+	if len(dirs) == 0 {
+		dirs = "/usr/local/share:/usr/share"
+	}
+	return append([]string{home}, filepath.SplitList(dirs)...)
+}
+
+// Applications reads the desktop entries in each of the directories given
+// (and their sub-directories), sorted by name. When two directories have a
+// desktop entry with the same ID, the one in the earlier directory is used.
+// Desktop entries that aren't applications or that shouldn't be displayed
+// are skipped.
+func Applications(dirs []string) []*Entry {
+	seen := make(map[string]bool)
+	entries := make([]*Entry, 0)
+	for _, dir := range dirs {
+		dir := dir
+		walk := func(fpath string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			if !strings.HasSuffix(fpath, ".desktop") {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, fpath)
+			if err != nil {
+				return nil
+			}
+			id := strings.Replace(rel, string(filepath.Separator), "-", -1)
+			if seen[id] {
+				return nil
+			}
+			seen[id] = true
+
+			f, err := os.Open(fpath)
+			if err != nil {
+				return nil
+			}
+			defer f.Close()
+
+			if e, ok := readDesktop(f); ok {
+				e.ID = id
+				entries = append(entries, e)
+			}
+			return nil
+		}
+		filepath.Walk(dir, walk)
+	}
+	sortByName(entries)
+	return entries
+}
+
+// readDesktop reads the keys of the "Desktop Entry" group of a desktop entry.
+// Only the unlocalized values are used. False is returned when the entry
+// isn't an application that should be shown in a launcher.
+func readDesktop(r io.Reader) (*Entry, bool) {
+	e := &Entry{Desktop: true}
+	typ, hidden := "", false
+
+	scanner := bufio.NewScanner(r)
+	inGroup := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			inGroup = line == "[Desktop Entry]"
+			continue
+		}
+		if !inGroup {
+			continue
+		}
+		eq := strings.Index(line, "=")
+		if eq == -1 {
+			continue
+		}
+		key := strings.TrimSpace(line[:eq])
+		val := strings.TrimSpace(line[eq+1:])
+		switch key {
+		case "Type":
+			typ = val
+		case "Name":
+			e.Name = val
+		case "Exec":
+			e.Exec = stripFieldCodes(val)
+		case "Icon":
+			e.Icon = val
+		case "Categories":
+			for _, cat := range strings.Split(val, ";") {
+				if len(cat) > 0 {
+					e.Categories = append(e.Categories, cat)
+				}
+			}
+		case "Terminal":
+			e.Terminal = val == "true"
+		case "NoDisplay", "Hidden":
+			hidden = hidden || val == "true"
+		}
+	}
+	if typ != "Application" || hidden {
+		return nil, false
+	}
+	if len(e.Name) == 0 || len(e.Exec) == 0 {
+		return nil, false
+	}
+	return e, true
+}
+
+// stripFieldCodes removes the field codes (like %f or %U) from the Exec key
+// of a desktop entry, since the launcher never passes files or URLs. "%%" is
+// replaced with a single "%".
+func stripFieldCodes(exec string) string {
+	var buf []byte
+	for i := 0; i < len(exec); i++ {
+		if exec[i] != '%' {
+			buf = append(buf, exec[i])
+			continue
+		}
+		if i+1 < len(exec) && exec[i+1] == '%' {
+			buf = append(buf, '%')
+		}
+		i++
+	}
+	return strings.Join(strings.Fields(string(buf)), " ")
+}
+
+// Commands returns an entry for every executable in the directories of
+// pathList (formatted like $PATH), sorted by name. When two directories
+// have an executable with the same name, only the first one is used, just
+// like the shell would.
+func Commands(pathList string) []*Entry {
+	seen := make(map[string]bool)
+	entries := make([]*Entry, 0)
+	for _, dir := range filepath.SplitList(pathList) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name := file.Name()
+			if seen[name] {
+				continue
+			}
+
+			// Follow symbolic links to find out what they point to.
+			info, err := os.Stat(path.Join(dir, name))
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			seen[name] = true
+			entries = append(entries, &Entry{
+				ID:   name,
+				Name: name,
+				Exec: name,
+			})
+		}
+	}
+	sortByName(entries)
+	return entries
+}
+
+func sortByName(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Name) <
+			strings.ToLower(entries[j].Name)
+	})
+}
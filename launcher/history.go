@@ -0,0 +1,125 @@
+package launcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Use records how many times an entry was launched, and when it was last
+// launched.
+type Use struct {
+	Count int
+	Last  time.Time
+}
+
+// History remembers the entries that have been launched, keyed by entry ID.
+// It is safe to use from multiple goroutines.
+type History struct {
+	lock sync.Mutex
+	Uses map[string]*Use
+}
+
+func NewHistory() *History {
+	return &History{Uses: make(map[string]*Use)}
+}
+
+// LoadHistory reads a history from the file at fpath. If the file does not
+// exist, an empty history is returned along with the error.
+func LoadHistory(fpath string) (*History, error) {
+	h := NewHistory()
+	bs, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(bs, h); err != nil {
+		return NewHistory(), err
+	}
+	if h.Uses == nil {
+		h.Uses = make(map[string]*Use)
+	}
+	return h, nil
+}
+
+// Save writes the history to the file at fpath, replacing its contents.
+// The file is written to a temporary file first and then renamed.
+func (h *History) Save(fpath string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	bs, err := json.MarshalIndent(h, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := fpath + ".tmp"
+	if err := ioutil.WriteFile(tmp, bs, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fpath)
+}
+
+// Add records that the entry with the given ID was launched at now.
+func (h *History) Add(id string, now time.Time) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	use, ok := h.Uses[id]
+	if !ok {
+		use = &Use{}
+		h.Uses[id] = use
+	}
+	use.Count++
+	use.Last = now
+}
+
+// Score returns the frecency of the entry with the given ID at now. It is
+// the number of times the entry was launched, weighted by how long ago it
+// was last launched. An entry that was never launched has a score of 0.
+func (h *History) Score(id string, now time.Time) int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	use, ok := h.Uses[id]
+	if !ok {
+		return 0
+	}
+	return use.Count * recencyWeight(now.Sub(use.Last))
+}
+
+// recencyWeight returns the weight given to the launches of an entry that
+// was last launched age ago.
+func recencyWeight(age time.Duration) int {
+	day := 24 * time.Hour
+	switch {
+	case age < 4*day:
+		return 100
+	case age < 14*day:
+		return 70
+	case age < 31*day:
+		return 50
+	case age < 90*day:
+		return 30
+	}
+	return 10
+}
+
+// Sort orders entries by their score at now, from highest to lowest. Entries
+// with the same score are sorted by name.
+func (h *History) Sort(entries []*Entry, now time.Time) {
+	scores := make(map[string]int, len(entries))
+	for _, e := range entries {
+		scores[e.ID] = h.Score(e.ID, now)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		si, sj := scores[entries[i].ID], scores[entries[j].ID]
+		if si != sj {
+			return si > sj
+		}
+		return strings.ToLower(entries[i].Name) <
+			strings.ToLower(entries[j].Name)
+	})
+}
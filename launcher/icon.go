@@ -0,0 +1,55 @@
+package launcher
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// iconSizes are the sizes of the hicolor icon theme that are searched, in
+// order of preference.
+var iconSizes = []string{
+	"48x48", "32x32", "64x64", "24x24", "128x128", "256x256", "16x16",
+}
+
+// IconPath returns the path of a PNG image for the icon of a desktop entry,
+// or an empty string if none can be found. An absolute icon is used as is.
+// Otherwise, the icon is looked up in the hicolor icon theme of each of
+// dirs (see DataDirs), in ~/.icons and then in /usr/share/pixmaps.
+//
+// Only PNG images are returned, since other formats (like SVG or XPM) can't
+// be drawn by Wingo.
+func IconPath(icon string, dirs []string) string {
+	if len(icon) == 0 {
+		return ""
+	}
+	if path.IsAbs(icon) {
+		if strings.HasSuffix(icon, ".png") && exists(icon) {
+			return icon
+		}
+		return ""
+	}
+
+	name := strings.TrimSuffix(icon, ".png") + ".png"
+	themes := []string{path.Join(os.Getenv("HOME"), ".icons", "hicolor")}
+	for _, dir := range dirs {
+		themes = append(themes, path.Join(dir, "icons", "hicolor"))
+	}
+	for _, size := range iconSizes {
+		for _, theme := range themes {
+			fpath := path.Join(theme, size, "apps", name)
+			if exists(fpath) {
+				return fpath
+			}
+		}
+	}
+	if fpath := path.Join("/usr/share/pixmaps", name); exists(fpath) {
+		return fpath
+	}
+	return ""
+}
+
+func exists(fpath string) bool {
+	info, err := os.Stat(fpath)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,145 @@
+package launcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadDesktop(t *testing.T) {
+	desktop := `
+[Desktop Entry]
+Type=Application
+Name=Text Editor
+Name[fr]=Editeur de texte
+Exec=editor --new-window %U
+Icon=accessories-text-editor
+Categories=Utility;TextEditor;
+
+[Desktop Action new]
+Name=New Window
+Exec=editor --other
+`
+	e, ok := readDesktop(strings.NewReader(desktop))
+	if !ok {
+		t.Fatalf("expected the entry to be shown")
+	}
+	if e.Name != "Text Editor" || e.Exec != "editor --new-window" {
+		t.Fatalf("unexpected name or exec: %+v", e)
+	}
+	if len(e.Categories) != 2 || e.Categories[1] != "TextEditor" {
+		t.Fatalf("unexpected categories: %v", e.Categories)
+	}
+
+	hidden := "[Desktop Entry]\nType=Application\nName=x\nExec=x\n" +
+		"NoDisplay=true\n"
+	if _, ok := readDesktop(strings.NewReader(hidden)); ok {
+		t.Fatalf("expected a NoDisplay entry to be skipped")
+	}
+	link := "[Desktop Entry]\nType=Link\nName=x\nURL=http://x\n"
+	if _, ok := readDesktop(strings.NewReader(link)); ok {
+		t.Fatalf("expected a link to be skipped")
+	}
+}
+
+func TestStripFieldCodes(t *testing.T) {
+	tests := map[string]string{
+		"foo %f":          "foo",
+		"foo %U --bar":    "foo --bar",
+		"printf 100%%":    "printf 100%",
+		"foo --icon %i %": "foo --icon",
+	}
+	for exec, expected := range tests {
+		if got := stripFieldCodes(exec); got != expected {
+			t.Errorf("stripFieldCodes(%q) = %q, expected %q",
+				exec, got, expected)
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	dir1, err := ioutil.TempDir("", "wingo-launcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir1)
+	dir2, err := ioutil.TempDir("", "wingo-launcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir2)
+
+	write := func(fpath string, mode os.FileMode) {
+		if err := ioutil.WriteFile(fpath, nil, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(path.Join(dir1, "b"), 0755)
+	write(path.Join(dir1, "notexec"), 0644)
+	write(path.Join(dir2, "a"), 0755)
+	write(path.Join(dir2, "b"), 0755)
+
+	entries := Commands(dir1 + string(os.PathListSeparator) + dir2)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(entries))
+	}
+	if entries[0].Name != "a" || entries[1].Name != "b" {
+		t.Fatalf("expected commands a and b, got %s and %s",
+			entries[0].Name, entries[1].Name)
+	}
+}
+
+func TestHistorySort(t *testing.T) {
+	now := time.Now()
+	h := NewHistory()
+	h.Add("old", now.Add(-100*24*time.Hour))
+	h.Add("old", now.Add(-100*24*time.Hour))
+	h.Add("old", now.Add(-100*24*time.Hour))
+	h.Add("new", now)
+
+	entries := []*Entry{
+		{ID: "never", Name: "a"},
+		{ID: "old", Name: "b"},
+		{ID: "new", Name: "c"},
+	}
+	h.Sort(entries, now)
+
+	// 1 recent launch beats 3 launches from 100 days ago.
+	for i, id := range []string{"new", "old", "never"} {
+		if entries[i].ID != id {
+			t.Fatalf("expected %s at position %d, got %s",
+				id, i, entries[i].ID)
+		}
+	}
+}
+
+func TestHistorySaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wingo-launcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fpath := path.Join(dir, "launcher.json")
+	if _, err := LoadHistory(fpath); err == nil {
+		t.Fatalf("expected an error loading a missing history")
+	}
+
+	now := time.Now()
+	h := NewHistory()
+	h.Add("firefox.desktop", now)
+	h.Add("firefox.desktop", now)
+	if err := h.Save(fpath); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadHistory(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.Score("firefox.desktop", now), 200; got != want {
+		t.Fatalf("expected a score of %d, got %d", want, got)
+	}
+}
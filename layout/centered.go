@@ -0,0 +1,65 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// CenteredMaster stacks the masters in a column in the middle of the screen.
+// The other clients are stacked in columns on either side, alternating
+// between the right and the left. If there is only one such client, the
+// masters are on the left and that client is on the right.
+type CenteredMaster struct {
+	ordered
+}
+
+func NewCenteredMaster() *CenteredMaster {
+	lay := &CenteredMaster{newOrdered()}
+	lay.arrange = lay.rects
+	return lay
+}
+
+func (lay *CenteredMaster) Name() string {
+	return "CenteredMaster"
+}
+
+func (lay *CenteredMaster) rects(geom xrect.Rect, gap int) []xrect.Rect {
+	n := len(lay.clients)
+	masters := lay.masters
+	if masters > n {
+		masters = n
+	}
+	slaves := n - masters
+
+	switch {
+	case masters == 0:
+		return stackRects(geom, true, n, gap)
+	case slaves == 0:
+		return stackRects(geom, false, n, gap)
+	case slaves == 1:
+		area, rest := splitRect(geom, true, lay.masterProp, gap)
+		return append(stackRects(area, false, masters, gap), rest)
+	}
+
+	// Split the space into three columns, where the masters get masterProp
+	// of the width and the sides split the rest evenly.
+	x, y, w, h := xrect.Pieces(geom)
+	avail := w - 2*gap
+	mw := int(float64(avail) * lay.masterProp)
+	lw := (avail - mw) / 2
+	rw := avail - mw - lw
+	left := xrect.New(x, y, lw, h)
+	center := xrect.New(x+lw+gap, y, mw, h)
+	right := xrect.New(x+lw+gap+mw+gap, y, rw, h)
+
+	lefts := stackRects(left, false, slaves/2, gap)
+	rights := stackRects(right, false, slaves-slaves/2, gap)
+	rects := stackRects(center, false, masters, gap)
+	for i := 0; i < slaves; i++ {
+		if i%2 == 0 {
+			rects = append(rects, rights[i/2])
+		} else {
+			rects = append(rects, lefts[i/2])
+		}
+	}
+	return rects
+}
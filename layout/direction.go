@@ -0,0 +1,67 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// Closest returns the index of the rectangle in rects that is closest to
+// from in the direction given, or -1 if there is no rectangle in that
+// direction. A rectangle is in a direction when its center is past the
+// center of from in that direction.
+//
+// Rectangles are ranked by the distance between their centers along the
+// direction, plus the distance between their centers across it. The
+// distance across is ignored when the rectangles overlap across the
+// direction, so that a neighbor sharing an edge with from always wins over
+// one that is only diagonally adjacent.
+func Closest(from xrect.Rect, dir Direction, rects []xrect.Rect) int {
+	best, bestDist := -1, 0
+	for i, r := range rects {
+		along, across := dir.distances(from, r)
+		if along <= 0 {
+			continue
+		}
+		if dist := along + across; best == -1 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// distances returns how far the center of r is from the center of from
+// along the direction (negative if r is in the opposite direction) and
+// across it (zero if the rectangles overlap across the direction).
+func (dir Direction) distances(from, r xrect.Rect) (along, across int) {
+	fx, fy := center(from)
+	rx, ry := center(r)
+	if dir.horizontal() {
+		along, across = rx-fx, abs(ry-fy)
+		if spans(from.Y(), from.Height(), r.Y(), r.Height()) {
+			across = 0
+		}
+	} else {
+		along, across = ry-fy, abs(rx-fx)
+		if spans(from.X(), from.Width(), r.X(), r.Width()) {
+			across = 0
+		}
+	}
+	return along * dir.delta(), across
+}
+
+// center returns the point in the middle of r.
+func center(r xrect.Rect) (int, int) {
+	return r.X() + r.Width()/2, r.Y() + r.Height()/2
+}
+
+// spans returns true if the segments starting at p1 and p2 with lengths len1
+// and len2 overlap.
+func spans(p1, len1, p2, len2 int) bool {
+	return p1 < p2+len2 && p2 < p1+len1
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// fibonacci is the basis of the Spiral and Dwindle layouts. The masters are
+// stacked in a master area on the left. Every other client then takes half
+// of the space left over, alternating between splitting it side by side and
+// one above the other.
+type fibonacci struct {
+	ordered
+	spiral bool
+}
+
+// Spiral is a fibonacci layout where the space left over moves around the
+// screen clockwise, so that clients spiral in toward the center.
+type Spiral struct {
+	fibonacci
+}
+
+// Dwindle is a fibonacci layout where the space left over is always to the
+// right or below, so that clients get smaller toward the bottom right corner.
+type Dwindle struct {
+	fibonacci
+}
+
+func NewSpiral() *Spiral {
+	lay := &Spiral{fibonacci{ordered: newOrdered(), spiral: true}}
+	lay.arrange = lay.rects
+	return lay
+}
+
+func NewDwindle() *Dwindle {
+	lay := &Dwindle{fibonacci{ordered: newOrdered(), spiral: false}}
+	lay.arrange = lay.rects
+	return lay
+}
+
+func (lay *Spiral) Name() string {
+	return "Spiral"
+}
+
+func (lay *Dwindle) Name() string {
+	return "Dwindle"
+}
+
+func (lay *fibonacci) rects(geom xrect.Rect, gap int) []xrect.Rect {
+	n := len(lay.clients)
+	masters := lay.masters
+	if masters >= n {
+		return stackRects(geom, false, n, gap)
+	}
+
+	rects := make([]xrect.Rect, 0, n)
+	rest, step := geom, 0
+	if masters > 0 {
+		var area xrect.Rect
+		area, rest = splitRect(geom, true, lay.masterProp, gap)
+		rects = append(rects, stackRects(area, false, masters, gap)...)
+		step = 1
+	}
+	for i := masters; i < n-1; i++ {
+		horizontal := step%2 == 0
+		var r xrect.Rect
+		if lay.spiral && step%4 >= 2 {
+			rest, r = splitRect(rest, horizontal, 0.5, gap)
+		} else {
+			r, rest = splitRect(rest, horizontal, 0.5, gap)
+		}
+		rects = append(rects, r)
+		step++
+	}
+	return append(rects, rest)
+}
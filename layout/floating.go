@@ -1,37 +1,130 @@
 package layout
 
 import (
-	"math/rand"
-	"time"
-
 	"github.com/BurntSushi/xgbutil/xrect"
 )
 
 type Floating struct {
-	clients []Client
-	geom    xrect.Rect
+	clients   []Client
+	geom      xrect.Rect
+	placement Placement
+	cascade   int // the number of clients placed since the cascade started
 }
 
 func NewFloating() *Floating {
 	return &Floating{
-		clients: make([]Client, 0),
+		clients:   make([]Client, 0),
+		placement: PlaceRandom,
 	}
 }
 
-func (f *Floating) InitialPlacement(c Client) {
-	rand.Seed(time.Now().UnixNano())
+// SetPlacement sets the policy used to place new clients.
+func (f *Floating) SetPlacement(p Placement) {
+	f.placement = p
+}
+
+func (f *Floating) Placement() Placement {
+	return f.placement
+}
+
+// InitialPlacement moves a new client according to the placement policy of
+// the layout. Transient clients are only placed if the policy is
+// PlaceParent.
+func (f *Floating) InitialPlacement(c Client, info PlaceInfo) {
+	if f.geom == nil {
+		return
+	}
+	if info.Parent != nil && f.placement != PlaceParent {
+		return
+	}
+
 	cgeom := c.Geom()
+	w, h := cgeom.Width(), cgeom.Height()
+
+	var x, y int
+	switch f.placement {
+	case PlacePointer:
+		x, y = centerOn(info.PointerX, info.PointerY, w, h)
+	case PlaceCenter:
+		x, y = centerOn(f.geom.X()+f.geom.Width()/2,
+			f.geom.Y()+f.geom.Height()/2, w, h)
+	case PlaceCascade:
+		x, y = f.cascadePosition(w, h)
+	case PlaceSmart:
+		x, y = f.smartPosition(c, w, h)
+	case PlaceParent:
+		if info.Parent == nil {
+			x, y = f.smartPosition(c, w, h)
+		} else {
+			pgeom := info.Parent.Geom()
+			x, y = centerOn(pgeom.X()+pgeom.Width()/2,
+				pgeom.Y()+pgeom.Height()/2, w, h)
+		}
+	default:
+		x, y = f.randomPosition(w, h)
+	}
+	x, y = clamp(f.geom, x, y, w, h)
+	f.Move(c, x, y)
+}
 
+func (f *Floating) randomPosition(width, height int) (int, int) {
 	x, y := f.geom.X(), f.geom.Y()
-	xlimit := f.geom.Width() - cgeom.Width()
-	ylimit := f.geom.Height() - cgeom.Height()
-	if xlimit > 0 {
-		x += rand.Intn(xlimit)
+	if xlimit := f.geom.Width() - width; xlimit > 0 {
+		x += rng.Intn(xlimit)
 	}
-	if ylimit > 0 {
-		y += rand.Intn(ylimit)
+	if ylimit := f.geom.Height() - height; ylimit > 0 {
+		y += rng.Intn(ylimit)
 	}
-	f.Move(c, x, y)
+	return x, y
+}
+
+func (f *Floating) cascadePosition(width, height int) (int, int) {
+	offset := f.cascade * cascadeStep
+	if offset+width > f.geom.Width() || offset+height > f.geom.Height() {
+		f.cascade, offset = 0, 0
+	}
+	f.cascade++
+	return f.geom.X() + offset, f.geom.Y() + offset
+}
+
+// smartPosition tries the top left corner of the workspace, along with the
+// positions right next to each floating client, and returns the one where
+// the client would overlap the least with the other floating clients. Ties
+// go to the position closest to the top, and then to the left.
+func (f *Floating) smartPosition(c Client, width, height int) (int, int) {
+	others := make([]xrect.Rect, 0, len(f.clients))
+	for _, other := range f.clients {
+		if other == c {
+			continue
+		}
+		if _, ok := other.Layout().(*Floating); ok {
+			others = append(others, other.Geom())
+		}
+	}
+
+	candidates := [][2]int{{f.geom.X(), f.geom.Y()}}
+	for _, o := range others {
+		right, below := o.X()+o.Width(), o.Y()+o.Height()
+		candidates = append(candidates,
+			[2]int{right, o.Y()}, [2]int{o.X(), below},
+			[2]int{right, f.geom.Y()}, [2]int{f.geom.X(), below})
+	}
+
+	bestx, besty, best := 0, 0, -1
+	for _, cand := range candidates {
+		x, y := clamp(f.geom, cand[0], cand[1], width, height)
+		r := xrect.New(x, y, width, height)
+		area := 0
+		for _, o := range others {
+			area += overlap(r, o)
+		}
+		if best == -1 || area < best ||
+			(area == best && (y < besty || (y == besty && x < bestx))) {
+
+			bestx, besty, best = x, y, area
+		}
+	}
+	return bestx, besty
 }
 
 func (f *Floating) Place()   {}
@@ -0,0 +1,39 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// Gaps describes the empty space left around tiled clients.
+type Gaps struct {
+	// The space between two tiled clients.
+	Inner int
+
+	// The space between tiled clients and the edges of the workspace.
+	Outer int
+
+	// When true, there are no gaps at all if only one client is tiled.
+	Smart bool
+}
+
+// apply returns the geometry that tiled clients should be placed in, and the
+// inner gap to leave between them, given the number of clients tiled.
+// If the geometry would be too small, no gaps are used.
+func (g Gaps) apply(geom xrect.Rect, tiled int) (xrect.Rect, int) {
+	if g.Smart && tiled <= 1 {
+		return geom, 0
+	}
+	inner, outer := max(g.Inner, 0), max(g.Outer, 0)
+	w, h := geom.Width()-2*outer, geom.Height()-2*outer
+	if w < 1 || h < 1 {
+		return geom, 0
+	}
+	return xrect.New(geom.X()+outer, geom.Y()+outer, w, h), inner
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
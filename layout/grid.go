@@ -0,0 +1,88 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// Grid tiles clients in rows of the same height, where every client in a row
+// has the same width. By default, the number of columns is chosen so that
+// the grid is as square as possible. MastersMore and MastersFewer add or
+// remove a column instead. Any clients left over are put in the last row.
+type Grid struct {
+	ordered
+	columns int // 0 means automatic
+}
+
+func NewGrid() *Grid {
+	lay := &Grid{ordered: newOrdered()}
+	lay.arrange = lay.rects
+	return lay
+}
+
+func (lay *Grid) Name() string {
+	return "Grid"
+}
+
+// There is no master area in a grid layout.
+func (lay *Grid) ResizeMaster(amount float64) {
+}
+
+// MastersMore adds a column to the grid.
+func (lay *Grid) MastersMore() {
+	if cols := lay.numColumns(); cols < len(lay.clients) {
+		lay.columns = cols + 1
+		lay.Place()
+	}
+}
+
+// MastersFewer removes a column from the grid.
+func (lay *Grid) MastersFewer() {
+	if cols := lay.numColumns(); cols > 1 {
+		lay.columns = cols - 1
+		lay.Place()
+	}
+}
+
+// State uses the number of columns (zero when it is chosen automatically) in
+// place of the number of masters.
+func (lay *Grid) State() State {
+	s := lay.ordered.State()
+	s.Masters, s.MasterProportion = lay.columns, 0
+	return s
+}
+
+func (lay *Grid) SetState(s State) {
+	if s.Masters >= 0 {
+		lay.columns = s.Masters
+	}
+	lay.clients = reorder(lay.clients, s.Clients)
+}
+
+func (lay *Grid) numColumns() int {
+	n := len(lay.clients)
+	cols := lay.columns
+	if cols <= 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(n))))
+	}
+	if cols > n {
+		cols = n
+	}
+	return cols
+}
+
+func (lay *Grid) rects(geom xrect.Rect, gap int) []xrect.Rect {
+	n, cols := len(lay.clients), lay.numColumns()
+	rows := (n + cols - 1) / cols
+
+	rects := make([]xrect.Rect, 0, n)
+	for i, row := range stackRects(geom, false, rows, gap) {
+		inRow := cols
+		if i == rows-1 {
+			inRow = n - cols*(rows-1)
+		}
+		rects = append(rects, stackRects(row, true, inRow, gap)...)
+	}
+	return rects
+}
@@ -1,6 +1,8 @@
 package layout
 
 import (
+	"strings"
+
 	"github.com/BurntSushi/xgbutil/xrect"
 )
 
@@ -12,6 +14,46 @@ const (
 	AutoTileVertical = iota
 )
 
+// Direction is used by layouts that can move clients around geometrically.
+type Direction int
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+// ParseDirection converts "left", "right", "up" or "down" (case insensitive)
+// to a Direction. The second return value is false if the string isn't a
+// direction.
+func ParseDirection(s string) (Direction, bool) {
+	switch strings.ToLower(s) {
+	case "left":
+		return Left, true
+	case "right":
+		return Right, true
+	case "up":
+		return Up, true
+	case "down":
+		return Down, true
+	}
+	return 0, false
+}
+
+// horizontal returns true if the direction is left or right.
+func (dir Direction) horizontal() bool {
+	return dir == Left || dir == Right
+}
+
+// delta returns -1 if the direction is left or up, and 1 otherwise.
+func (dir Direction) delta() int {
+	if dir == Left || dir == Up {
+		return -1
+	}
+	return 1
+}
+
 type Layout interface {
 	Name() string
 	SetGeom(geom xrect.Rect)
@@ -30,7 +72,9 @@ type Layout interface {
 
 type Floater interface {
 	Layout
-	InitialPlacement(c Client)
+	InitialPlacement(c Client, info PlaceInfo)
+	SetPlacement(p Placement)
+	Placement() Placement
 	Save()
 	Reposition()
 }
@@ -47,4 +91,7 @@ type AutoTiler interface {
 	MakeMaster()
 	MastersMore()
 	MastersFewer()
+	SetGaps(gaps Gaps)
+	State() State
+	SetState(s State)
 }
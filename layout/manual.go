@@ -0,0 +1,431 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// Manual is a tiling layout where clients are arranged by hand in a tree of
+// horizontal and vertical splits (much like i3). A new client is added right
+// after the focused client, in the same split. The focused client can then
+// be given a split of its own, moved around the tree or resized.
+type Manual struct {
+	store *tree
+	root  splitter
+	geom  xrect.Rect
+}
+
+func NewManual() *Manual {
+	lay := &Manual{
+		store: newTree(),
+		root:  newHSplit(nil),
+	}
+	lay.root.SetProportion(fullPortion)
+	lay.store.setChild(lay.root)
+	return lay
+}
+
+func (lay *Manual) Name() string {
+	return "Manual"
+}
+
+func (lay *Manual) Destroy() {
+}
+
+func (lay *Manual) SetGeom(geom xrect.Rect) {
+	lay.geom = geom
+}
+
+func (lay *Manual) Place() {
+	lay.store.place(lay.geom)
+}
+
+func (lay *Manual) Unplace() {}
+
+func (lay *Manual) Exists(c Client) bool {
+	return lay.store.findLeaf(c) != nil
+}
+
+func (lay *Manual) Add(c Client) {
+	if lay.Exists(c) {
+		return
+	}
+	lf := lay.leafCurrent()
+	if lf == nil {
+		lay.root.AddNode(newLeaf(lay.root, c), true)
+		return
+	}
+	lf.parent.InsertNode(newLeaf(lf.parent, c), lf.parent.ChildIndex(lf)+1)
+}
+
+func (lay *Manual) Remove(c Client) {
+	if lf := lay.store.findLeaf(c); lf != nil {
+		lay.removeNode(lf, lay.root)
+		lay.simplify()
+	}
+}
+
+// Split puts the focused client into a new split, so that the next client
+// added is placed next to it horizontally (side by side) or vertically (one
+// above the other). If the focused client is already alone in its split,
+// only the orientation of that split is changed.
+func (lay *Manual) Split(horizontal bool) {
+	lf := lay.leafCurrent()
+	if lf == nil {
+		return
+	}
+	if lf.parent.Size() == 1 {
+		lay.orient(lf.parent, horizontal)
+		lay.Place()
+		return
+	}
+
+	s := newSplit(horizontal, lf.parent)
+	lf.parent.ReplaceNode(lf, s)
+	lf.SetParent(s)
+	s.AddNode(lf, true)
+}
+
+// ToggleSplit switches the orientation of the split containing the focused
+// client between horizontal and vertical.
+func (lay *Manual) ToggleSplit() {
+	if lf := lay.leafCurrent(); lf != nil {
+		lay.orient(lf.parent, !isHorizontal(lf.parent))
+		lay.Place()
+	}
+}
+
+// MoveDirection moves the focused client in the direction given. Within a
+// split of the same orientation, the client switches places with its
+// neighbor (or moves into it, if the neighbor is a split). At the edge of a
+// split, the client moves out into the closest ancestor split of the same
+// orientation. If there is no such split, the whole tree is wrapped in one.
+func (lay *Manual) MoveDirection(dir Direction) {
+	lf := lay.leafCurrent()
+	if lf == nil || len(lay.leaves()) < 2 {
+		return
+	}
+	horizontal, delta := dir.horizontal(), dir.delta()
+
+	p := lf.parent
+	if isHorizontal(p) == horizontal {
+		i := p.ChildIndex(lf)
+		if j := i + delta; j >= 0 && j < p.Size() {
+			if sib, ok := p.Child(j).(splitter); ok {
+				lay.removeNode(lf, p)
+				lf.SetParent(sib)
+				if isHorizontal(sib) == horizontal && delta > 0 {
+					sib.InsertNode(lf, 0)
+				} else {
+					sib.InsertNode(lf, sib.Size())
+				}
+			} else {
+				p.SwapNodes(i, j)
+			}
+			lay.simplify()
+			lay.Place()
+			return
+		}
+	}
+
+	// Find the closest ancestor with the right orientation, and remember
+	// which of its children the client is moving out of.
+	var child node = p
+	anc, _ := p.Parent().(splitter)
+	for anc != nil && isHorizontal(anc) != horizontal {
+		child = anc
+		anc, _ = anc.Parent().(splitter)
+	}
+	if anc == nil {
+		if isHorizontal(lay.root) == horizontal {
+			return // already at the edge
+		}
+		old := lay.root
+		lay.root = newSplit(horizontal, nil)
+		lay.root.SetProportion(fullPortion)
+		lay.store.setChild(lay.root)
+
+		old.SetParent(lay.root)
+		lay.root.AddNode(old, true)
+
+		anc, child = lay.root, old
+	}
+
+	i := anc.ChildIndex(child)
+	lay.removeNode(lf, anc)
+	if anc.ChildIndex(child) > -1 && delta > 0 {
+		i++
+	}
+	lf.SetParent(anc)
+	anc.InsertNode(lf, i)
+
+	lay.simplify()
+	lay.Place()
+}
+
+// ResizeEdge moves the edge of the focused client in the direction given by
+// amount, which is a ratio of the split it's in. A positive amount grows the
+// client and a negative amount shrinks it. Only the neighbor on the other
+// side of the edge is resized to compensate. If the client has no neighbor
+// in that direction, the closest ancestor split that does is resized.
+func (lay *Manual) ResizeEdge(dir Direction, amount float64) {
+	lf := lay.leafCurrent()
+	if lf == nil {
+		return
+	}
+	horizontal, delta := dir.horizontal(), dir.delta()
+
+	var n node = lf
+	for {
+		p, ok := n.Parent().(splitter)
+		if !ok || p == nil {
+			return
+		}
+		if isHorizontal(p) == horizontal {
+			if j := p.ChildIndex(n) + delta; j >= 0 && j < p.Size() {
+				lay.resizeBetween(p, n, p.Child(j), proportion(amount))
+				return
+			}
+		}
+		n = p
+	}
+}
+
+func (lay *Manual) resizeBetween(p splitter, n, sib node, amount proportion) {
+	newProp := n.Proportion() + amount
+	sibProp := sib.Proportion() - amount
+	if newProp < epsilon || sibProp < epsilon {
+		return
+	}
+
+	p.PropsSave()
+	n.SetProportion(newProp)
+	sib.SetProportion(sibProp)
+	if lay.store.place(lay.geom) {
+		p.PropsClear()
+	} else {
+		p.PropsRollback()
+	}
+}
+
+// ResizeMaster resizes the first child of the top-most split.
+func (lay *Manual) ResizeMaster(amount float64) {
+	if lay.root.Size() < 2 {
+		return
+	}
+	lay.root.PropsSave()
+
+	first := lay.root.Child(0)
+	lay.root.SetChildProportion(first, first.Proportion()+proportion(amount))
+	if lay.store.place(lay.geom) {
+		lay.root.PropsClear()
+	} else {
+		lay.root.PropsRollback()
+	}
+}
+
+func (lay *Manual) ResizeWindow(amount float64) {
+	if lf := lay.leafCurrent(); lf != nil && lf.parent.Size() > 1 {
+		lf.parent.PropsSave()
+
+		newProp := lf.Proportion() + proportion(amount)
+		lf.parent.SetChildProportion(lf, newProp)
+
+		if lay.store.place(lay.geom) {
+			lf.parent.PropsClear()
+		} else {
+			lf.parent.PropsRollback()
+		}
+	}
+}
+
+func (lay *Manual) Next() {
+	if next := lay.leafSibling(1); next != nil {
+		next.client.Focus()
+		next.client.Raise()
+	}
+}
+
+func (lay *Manual) Prev() {
+	if prev := lay.leafSibling(-1); prev != nil {
+		prev.client.Focus()
+		prev.client.Raise()
+	}
+}
+
+func (lay *Manual) SwitchNext() {
+	if next := lay.leafSibling(1); next != nil {
+		lay.store.switchClients(lay.leafCurrent(), next)
+		lay.Place()
+	}
+}
+
+func (lay *Manual) SwitchPrev() {
+	if prev := lay.leafSibling(-1); prev != nil {
+		lay.store.switchClients(lay.leafCurrent(), prev)
+		lay.Place()
+	}
+}
+
+// FocusMaster focuses the first client in the tree.
+func (lay *Manual) FocusMaster() {
+	if leaves := lay.leaves(); len(leaves) > 0 {
+		leaves[0].client.Focus()
+		leaves[0].client.Raise()
+	}
+}
+
+// MakeMaster switches the focused client with the first client in the tree.
+func (lay *Manual) MakeMaster() {
+	if lf := lay.leafCurrent(); lf != nil {
+		lay.store.switchClients(lf, lay.leaves()[0])
+		lay.Place()
+	}
+}
+
+// There is no master split in a manual layout.
+func (lay *Manual) MastersMore() {
+}
+
+// There is no master split in a manual layout.
+func (lay *Manual) MastersFewer() {
+}
+
+func (lay *Manual) SetGaps(gaps Gaps) {
+	lay.store.gaps = gaps
+}
+
+// State only includes the order of clients, since the shape of the tree
+// can't be described by a State.
+func (lay *Manual) State() State {
+	return State{Clients: clientIds(lay.store.clients())}
+}
+
+func (lay *Manual) SetState(s State) {
+	lay.store.setClientOrder(s.Clients)
+}
+
+func (lay *Manual) MROpt(c Client, flags, x, y, width, height int) {}
+
+func (lay *Manual) MoveResize(c Client, x, y, width, height int) {}
+
+func (lay *Manual) Move(c Client, x, y int) {}
+
+func (lay *Manual) Resize(c Client, width, height int) {}
+
+func (lay *Manual) leaves() []*leaf {
+	return lay.store.leaves()
+}
+
+func (lay *Manual) leafCurrent() *leaf {
+	var lf *leaf
+	lay.store.child.VisitLeafNodes(func(visit *leaf) bool {
+		if visit.client.IsActive() {
+			lf = visit
+			return false
+		}
+		return true
+	})
+	return lf
+}
+
+// leafSibling returns the leaf that is delta leaves away from the focused
+// leaf, wrapping around at either end of the tree.
+func (lay *Manual) leafSibling(delta int) *leaf {
+	leaves := lay.leaves()
+	for i, lf := range leaves {
+		if lf.client.IsActive() {
+			return leaves[(i+delta+len(leaves))%len(leaves)]
+		}
+	}
+	return nil
+}
+
+// removeNode removes n from its split. Any split left empty is removed as
+// well, up to (but not including) keep.
+func (lay *Manual) removeNode(n node, keep splitter) {
+	p := n.Parent().(splitter)
+	p.RemoveNode(n)
+	if p.Size() == 0 && p != lay.root && p != keep {
+		lay.removeNode(p, keep)
+	}
+}
+
+// simplify replaces every split that has only one child with that child.
+// If the top-most split ends up with only a split inside it, that split
+// becomes the top-most split.
+func (lay *Manual) simplify() {
+	var visit func(s splitter)
+	visit = func(s splitter) {
+		for i := 0; i < s.Size(); i++ {
+			child, ok := s.Child(i).(splitter)
+			if !ok {
+				continue
+			}
+			visit(child)
+			if child.Size() == 1 {
+				only := child.Child(0)
+				s.ReplaceNode(child, only)
+				only.SetParent(s)
+			}
+		}
+	}
+	visit(lay.root)
+
+	if lay.root.Size() == 1 {
+		if s, ok := lay.root.Child(0).(splitter); ok {
+			s.SetParent(nil)
+			s.SetProportion(fullPortion)
+			lay.root = s
+			lay.store.setChild(s)
+		}
+	}
+}
+
+// orient changes the orientation of a split by replacing it with a new
+// split that has the same children.
+func (lay *Manual) orient(s splitter, horizontal bool) {
+	if isHorizontal(s) == horizontal {
+		return
+	}
+
+	parent, _ := s.Parent().(splitter)
+	ns := newSplit(horizontal, parent)
+	for i := 0; i < s.Size(); i++ {
+		child := s.Child(i)
+		child.SetParent(ns)
+		splitOf(ns).children = append(splitOf(ns).children, child)
+	}
+
+	if parent == nil {
+		ns.SetProportion(fullPortion)
+		lay.root = ns
+		lay.store.setChild(ns)
+	} else {
+		parent.ReplaceNode(s, ns)
+	}
+}
+
+// newSplit creates an hsplit if horizontal is true and a vsplit otherwise.
+func newSplit(horizontal bool, parent node) splitter {
+	if horizontal {
+		return newHSplit(parent)
+	}
+	return newVSplit(parent)
+}
+
+// splitOf returns the split underlying an hsplit or a vsplit.
+func splitOf(s splitter) *split {
+	switch s := s.(type) {
+	case *hsplit:
+		return &s.split
+	case *vsplit:
+		return &s.split
+	}
+	panic("unreachable")
+}
+
+// isHorizontal returns true if the split places its children side by side.
+func isHorizontal(s splitter) bool {
+	_, ok := s.(*hsplit)
+	return ok
+}
@@ -9,6 +9,7 @@ import (
 type Maximized struct {
 	clients *list.List
 	geom    xrect.Rect
+	gaps    Gaps
 }
 
 func NewMaximized() *Maximized {
@@ -25,10 +26,37 @@ func (m *Maximized) SetGeom(geom xrect.Rect) {
 	m.geom = geom
 }
 
+// SetGaps sets the gaps of the layout. Since only one client is ever visible,
+// only the outer gap is used (and then only if smart gaps are off).
+func (m *Maximized) SetGaps(gaps Gaps) {
+	m.gaps = gaps
+}
+
+func (m *Maximized) State() State {
+	return State{Clients: clientIds(m.list())}
+}
+
+func (m *Maximized) SetState(s State) {
+	clients := reorder(m.list(), s.Clients)
+	m.clients.Init()
+	for _, c := range clients {
+		m.clients.PushBack(c)
+	}
+}
+
+func (m *Maximized) list() []Client {
+	clients := make([]Client, 0, m.clients.Len())
+	for e := m.clients.Front(); e != nil; e = e.Next() {
+		clients = append(clients, e.Value.(Client))
+	}
+	return clients
+}
+
 func (m *Maximized) Place() {
+	geom, _ := m.gaps.apply(m.geom, 1)
 	for el := m.clients.Front(); el != nil; el = el.Next() {
 		c := el.Value.(Client)
-		x, y, w, h := m.geom.X(), m.geom.Y(), m.geom.Width(), m.geom.Height()
+		x, y, w, h := geom.X(), geom.Y(), geom.Width(), geom.Height()
 		c.FrameTile()
 		c.MoveResize(x, y, w, h)
 	}
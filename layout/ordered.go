@@ -0,0 +1,255 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// ordered is the basis of tiling layouts that don't keep a tree of splits.
+// Instead, the geometry of every client is computed each time the layout is
+// placed, using only the order of its clients, the number of masters and
+// the proportion of the screen given to the masters.
+//
+// The arrange function must be set by each layout. Given the geometry to
+// fill (with the outer gap already taken out) and the inner gap, it returns
+// one rectangle for each client in the same order as clients.
+type ordered struct {
+	clients    []Client
+	masters    int
+	masterProp float64
+	geom       xrect.Rect
+	gaps       Gaps
+	arrange    func(geom xrect.Rect, gap int) []xrect.Rect
+}
+
+func newOrdered() ordered {
+	return ordered{
+		clients:    make([]Client, 0),
+		masters:    1,
+		masterProp: 0.5,
+	}
+}
+
+func (lay *ordered) Destroy() {
+}
+
+func (lay *ordered) SetGeom(geom xrect.Rect) {
+	lay.geom = geom
+}
+
+func (lay *ordered) SetGaps(gaps Gaps) {
+	lay.gaps = gaps
+}
+
+func (lay *ordered) Place() {
+	lay.place()
+}
+
+// place moves and resizes every client. If the geometry of any client would
+// be too small, nothing is done and false is returned.
+func (lay *ordered) place() bool {
+	if lay.geom == nil || len(lay.clients) == 0 {
+		return false
+	}
+	geom, gap := lay.gaps.apply(lay.geom, len(lay.clients))
+	rects := lay.arrange(geom, gap)
+	for _, r := range rects {
+		if r.Width() < 1 || r.Height() < 1 {
+			return false
+		}
+	}
+	for i, c := range lay.clients {
+		c.FrameTile()
+		c.MoveResize(xrect.Pieces(rects[i]))
+	}
+	return true
+}
+
+func (lay *ordered) Unplace() {}
+
+func (lay *ordered) Add(c Client) {
+	if !lay.Exists(c) {
+		lay.clients = append(lay.clients, c)
+	}
+}
+
+func (lay *ordered) Remove(c Client) {
+	if i := lay.index(c); i > -1 {
+		lay.clients = append(lay.clients[:i], lay.clients[i+1:]...)
+	}
+}
+
+func (lay *ordered) Exists(c Client) bool {
+	return lay.index(c) > -1
+}
+
+func (lay *ordered) ResizeMaster(amount float64) {
+	newProp := lay.masterProp + amount
+	if newProp < epsilon || newProp > 1-epsilon {
+		return
+	}
+
+	oldProp := lay.masterProp
+	lay.masterProp = newProp
+	if !lay.place() {
+		lay.masterProp = oldProp
+	}
+}
+
+// Clients cannot be resized individually when every geometry is computed.
+func (lay *ordered) ResizeWindow(amount float64) {
+}
+
+func (lay *ordered) Next() {
+	if i := lay.current(); i > -1 {
+		c := lay.clients[(i+1)%len(lay.clients)]
+		c.Focus()
+		c.Raise()
+	}
+}
+
+func (lay *ordered) Prev() {
+	if i := lay.current(); i > -1 {
+		c := lay.clients[(i-1+len(lay.clients))%len(lay.clients)]
+		c.Focus()
+		c.Raise()
+	}
+}
+
+func (lay *ordered) SwitchNext() {
+	if i := lay.current(); i > -1 {
+		lay.switchClients(i, (i+1)%len(lay.clients))
+	}
+}
+
+func (lay *ordered) SwitchPrev() {
+	if i := lay.current(); i > -1 {
+		lay.switchClients(i, (i-1+len(lay.clients))%len(lay.clients))
+	}
+}
+
+func (lay *ordered) FocusMaster() {
+	if len(lay.clients) > 0 {
+		lay.clients[0].Focus()
+		lay.clients[0].Raise()
+	}
+}
+
+func (lay *ordered) MakeMaster() {
+	if i := lay.current(); i > -1 {
+		lay.switchClients(i, 0)
+	}
+}
+
+func (lay *ordered) MastersMore() {
+	lay.masters += 1
+	lay.Place()
+}
+
+func (lay *ordered) MastersFewer() {
+	if lay.masters == 0 {
+		return
+	}
+	lay.masters -= 1
+	lay.Place()
+}
+
+func (lay *ordered) State() State {
+	return State{
+		Masters:          lay.masters,
+		MasterProportion: lay.masterProp,
+		Clients:          clientIds(lay.clients),
+	}
+}
+
+func (lay *ordered) SetState(s State) {
+	if s.Masters >= 0 {
+		lay.masters = s.Masters
+	}
+	if s.MasterProportion > epsilon && s.MasterProportion < 1-epsilon {
+		lay.masterProp = s.MasterProportion
+	}
+	lay.clients = reorder(lay.clients, s.Clients)
+}
+
+func (lay *ordered) MROpt(c Client, flags, x, y, width, height int) {}
+
+func (lay *ordered) MoveResize(c Client, x, y, width, height int) {}
+
+func (lay *ordered) Move(c Client, x, y int) {}
+
+func (lay *ordered) Resize(c Client, width, height int) {}
+
+func (lay *ordered) index(c Client) int {
+	for i, c2 := range lay.clients {
+		if c2 == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// current returns the index of the focused client, or -1 if it isn't in
+// this layout.
+func (lay *ordered) current() int {
+	for i, c := range lay.clients {
+		if c.IsActive() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (lay *ordered) switchClients(i, j int) {
+	if i == j {
+		return
+	}
+	lay.clients[i], lay.clients[j] = lay.clients[j], lay.clients[i]
+	lay.Place()
+}
+
+// splitRect splits r in two, either side by side (if horizontal is true) or
+// one above the other, with gap in between. The first rectangle gets prop of
+// the available space.
+func splitRect(r xrect.Rect, horizontal bool, prop float64,
+	gap int) (xrect.Rect, xrect.Rect) {
+
+	x, y, w, h := xrect.Pieces(r)
+	if horizontal {
+		first := int(float64(w-gap) * prop)
+		return xrect.New(x, y, first, h),
+			xrect.New(x+first+gap, y, w-first-gap, h)
+	}
+	first := int(float64(h-gap) * prop)
+	return xrect.New(x, y, w, first),
+		xrect.New(x, y+first+gap, w, h-first-gap)
+}
+
+// stackRects divides r into n rectangles of the same size, either side by
+// side (if horizontal is true) or one above the other, with gap in between
+// each of them. Any space left over from rounding goes to the last one.
+func stackRects(r xrect.Rect, horizontal bool, n, gap int) []xrect.Rect {
+	rects := make([]xrect.Rect, n)
+	if n == 0 {
+		return rects
+	}
+
+	x, y, w, h := xrect.Pieces(r)
+	size := h
+	if horizontal {
+		size = w
+	}
+	each := (size - gap*(n-1)) / n
+	for i := 0; i < n; i++ {
+		length := each
+		if i == n-1 {
+			length = size - (each+gap)*(n-1)
+		}
+		offset := i * (each + gap)
+		if horizontal {
+			rects[i] = xrect.New(x+offset, y, length, h)
+		} else {
+			rects[i] = xrect.New(x, y+offset, w, length)
+		}
+	}
+	return rects
+}
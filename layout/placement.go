@@ -0,0 +1,121 @@
+package layout
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// Placement is a policy used by floating layouts to place new clients.
+type Placement int
+
+const (
+	// PlaceRandom puts clients at a random position in the workspace.
+	PlaceRandom Placement = iota
+
+	// PlacePointer centers clients under the mouse pointer.
+	PlacePointer
+
+	// PlaceCenter centers clients in the workspace.
+	PlaceCenter
+
+	// PlaceCascade puts each client a bit below and to the right of the
+	// previous one, starting over in the top left corner of the workspace
+	// when a client would go past its edges.
+	PlaceCascade
+
+	// PlaceSmart puts clients where they overlap the least with the other
+	// floating clients.
+	PlaceSmart
+
+	// PlaceParent centers transient clients (like dialogs) over the client
+	// they belong to. Other clients are placed with PlaceSmart.
+	PlaceParent
+)
+
+// cascadeStep is the distance between two clients placed with PlaceCascade.
+const cascadeStep = 30
+
+var placementNames = map[Placement]string{
+	PlaceRandom:  "random",
+	PlacePointer: "pointer",
+	PlaceCenter:  "center",
+	PlaceCascade: "cascade",
+	PlaceSmart:   "smart",
+	PlaceParent:  "parent",
+}
+
+// rng is seeded only once, rather than every time a client is placed.
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// ParsePlacement converts the name of a placement policy (case insensitive)
+// to a Placement. The second return value is false if there is no policy
+// with that name.
+func ParsePlacement(s string) (Placement, bool) {
+	s = strings.ToLower(s)
+	for p, name := range placementNames {
+		if s == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+func (p Placement) String() string {
+	return placementNames[p]
+}
+
+// PlaceInfo is what a floating layout can use, besides the client itself, to
+// place a new client.
+type PlaceInfo struct {
+	// The position of the mouse pointer.
+	PointerX, PointerY int
+
+	// The client that a transient client belongs to, or nil.
+	Parent Client
+}
+
+// clamp returns a position for a client with the given size that is as
+// close to (x, y) as possible while keeping the client inside geom. If the
+// client is too big to fit, it is put at the top or left edge of geom.
+func clamp(geom xrect.Rect, x, y, width, height int) (int, int) {
+	if x+width > geom.X()+geom.Width() {
+		x = geom.X() + geom.Width() - width
+	}
+	if y+height > geom.Y()+geom.Height() {
+		y = geom.Y() + geom.Height() - height
+	}
+	if x < geom.X() {
+		x = geom.X()
+	}
+	if y < geom.Y() {
+		y = geom.Y()
+	}
+	return x, y
+}
+
+// centerOn returns the position that centers a client with the given size
+// on the point (cx, cy).
+func centerOn(cx, cy, width, height int) (int, int) {
+	return cx - width/2, cy - height/2
+}
+
+// overlap returns the area of the intersection of two rectangles.
+func overlap(r1, r2 xrect.Rect) int {
+	x1, y1 := max(r1.X(), r2.X()), max(r1.Y(), r2.Y())
+	x2 := min(r1.X()+r1.Width(), r2.X()+r2.Width())
+	y2 := min(r1.Y()+r1.Height(), r2.Y()+r2.Height())
+	if x2 <= x1 || y2 <= y1 {
+		return 0
+	}
+	return (x2 - x1) * (y2 - y1)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
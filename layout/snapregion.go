@@ -0,0 +1,81 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// SnapRegion is a part of a workspace that a floating client can be snapped
+// to: one of its halves, one of its quarters or its center.
+type SnapRegion int
+
+const (
+	SnapLeft SnapRegion = iota
+	SnapRight
+	SnapTop
+	SnapBottom
+	SnapTopLeft
+	SnapTopRight
+	SnapBottomLeft
+	SnapBottomRight
+	SnapCenter
+)
+
+var snapRegionNames = map[SnapRegion]string{
+	SnapLeft:        "left",
+	SnapRight:       "right",
+	SnapTop:         "top",
+	SnapBottom:      "bottom",
+	SnapTopLeft:     "top-left",
+	SnapTopRight:    "top-right",
+	SnapBottomLeft:  "bottom-left",
+	SnapBottomRight: "bottom-right",
+	SnapCenter:      "center",
+}
+
+// ParseSnapRegion converts the name of a snap region (case insensitive) to
+// a SnapRegion. The second return value is false if there is no region with
+// that name.
+func ParseSnapRegion(s string) (SnapRegion, bool) {
+	s = strings.ToLower(s)
+	for r, name := range snapRegionNames {
+		if s == name {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+func (r SnapRegion) String() string {
+	return snapRegionNames[r]
+}
+
+// Geom returns the geometry of the region in geom. Halves and quarters fill
+// their part of geom, while SnapCenter keeps the given width and height (as
+// long as they fit) and centers them in geom.
+func (r SnapRegion) Geom(geom xrect.Rect, width, height int) xrect.Rect {
+	x, y, w, h := xrect.Pieces(geom)
+	halfw, halfh := w/2, h/2
+	switch r {
+	case SnapLeft:
+		return xrect.New(x, y, halfw, h)
+	case SnapRight:
+		return xrect.New(x+halfw, y, w-halfw, h)
+	case SnapTop:
+		return xrect.New(x, y, w, halfh)
+	case SnapBottom:
+		return xrect.New(x, y+halfh, w, h-halfh)
+	case SnapTopLeft:
+		return xrect.New(x, y, halfw, halfh)
+	case SnapTopRight:
+		return xrect.New(x+halfw, y, w-halfw, halfh)
+	case SnapBottomLeft:
+		return xrect.New(x, y+halfh, halfw, h-halfh)
+	case SnapBottomRight:
+		return xrect.New(x+halfw, y+halfh, w-halfw, h-halfh)
+	}
+	width, height = min(width, w), min(height, h)
+	cx, cy := centerOn(x+halfw, y+halfh, width, height)
+	return xrect.New(cx, cy, width, height)
+}
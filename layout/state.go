@@ -0,0 +1,121 @@
+package layout
+
+import (
+	"encoding/json"
+
+	"github.com/BurntSushi/xgb/xproto"
+)
+
+// State describes how clients are arranged in a tiling layout, so that the
+// arrangement can be saved and restored later. Layouts only use the parts
+// of a state that make sense for them.
+type State struct {
+	// The number of masters (or the number of columns in the Grid layout).
+	// When it is negative, SetState leaves the number of masters alone.
+	Masters int
+
+	// The proportion of the screen given to the masters. It is zero when
+	// the layout has no master area.
+	MasterProportion float64
+
+	// The proportion of each client in the split that contains it, in the
+	// same order as Clients. It is nil when the layout doesn't keep a
+	// proportion for each client.
+	Proportions []float64
+
+	// The window ids of the clients in the layout, in order.
+	Clients []xproto.Window
+}
+
+// UnmarshalJSON decodes a state, leaving Masters negative if it is missing so
+// that restoring the state doesn't change the number of masters. (Otherwise
+// a state with only Clients would remove every master.)
+func (s *State) UnmarshalJSON(bs []byte) error {
+	// plain doesn't have this method, so that json.Unmarshal doesn't call it.
+	type plain State
+	p := plain{Masters: -1}
+	if err := json.Unmarshal(bs, &p); err != nil {
+		return err
+	}
+	*s = State(p)
+	return nil
+}
+
+// clientIds returns the window ids of clients.
+func clientIds(clients []Client) []xproto.Window {
+	ids := make([]xproto.Window, len(clients))
+	for i, c := range clients {
+		ids[i] = c.Id()
+	}
+	return ids
+}
+
+// reorder returns clients sorted so that the clients with the window ids in
+// order come first (in that order), followed by every other client in the
+// order they were already in.
+func reorder(clients []Client, order []xproto.Window) []Client {
+	sorted := make([]Client, 0, len(clients))
+	used := make(map[Client]bool, len(clients))
+	for _, id := range order {
+		for _, c := range clients {
+			if c.Id() == id && !used[c] {
+				sorted = append(sorted, c)
+				used[c] = true
+				break
+			}
+		}
+	}
+	for _, c := range clients {
+		if !used[c] {
+			sorted = append(sorted, c)
+		}
+	}
+	return sorted
+}
+
+// validProportions returns true if every proportion is positive and they add
+// up to a full split.
+func validProportions(props []float64) bool {
+	sum := proportion(0)
+	for _, p := range props {
+		if p < epsilon {
+			return false
+		}
+		sum += proportion(p)
+	}
+	return sum.equal(fullPortion)
+}
+
+// setProportions gives each child of s the proportion at the same index in
+// props. Nothing is changed unless there is a valid proportion for every
+// child.
+func setProportions(s splitter, props []float64) {
+	if s.Size() != len(props) || (len(props) > 0 && !validProportions(props)) {
+		return
+	}
+	for i, p := range props {
+		s.Child(i).SetProportion(proportion(p))
+	}
+}
+
+// SwapClients switches the places of two clients in a tiling layout and
+// places the layout again. Nothing happens unless both clients are in the
+// layout.
+func SwapClients(lay AutoTiler, c1, c2 Client) {
+	s := lay.State()
+	i, j := -1, -1
+	for k, id := range s.Clients {
+		switch id {
+		case c1.Id():
+			i = k
+		case c2.Id():
+			j = k
+		}
+	}
+	if i == -1 || j == -1 {
+		return
+	}
+	s.Clients[i], s.Clients[j] = s.Clients[j], s.Clients[i]
+	lay.SetState(s)
+	lay.Place()
+}
@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+)
+
+// TabBar is used by the Tabbed layout to show a tab for each of its clients.
+type TabBar interface {
+	// Height is the height of the tab bar, which is taken out of the space
+	// given to clients.
+	Height() int
+
+	// Update shows the tab bar at geom with a tab for each client. The tab
+	// of the client at index active is highlighted.
+	Update(geom xrect.Rect, clients []Client, active int)
+
+	Hide()
+	Destroy()
+}
+
+// Tabbed is like Maximized, except there is a tab bar above the clients
+// with a tab for each one of them. The tab of the last focused client is
+// highlighted, so it always shows which client is visible.
+type Tabbed struct {
+	ordered
+	bar     TabBar
+	barGeom xrect.Rect
+	active  Client
+}
+
+func NewTabbed(bar TabBar) *Tabbed {
+	lay := &Tabbed{ordered: newOrdered(), bar: bar}
+	lay.arrange = lay.rects
+	return lay
+}
+
+func (lay *Tabbed) Name() string {
+	return "Tabbed"
+}
+
+func (lay *Tabbed) Destroy() {
+	lay.bar.Destroy()
+}
+
+func (lay *Tabbed) Place() {
+	if !lay.place() {
+		lay.bar.Hide()
+		return
+	}
+	lay.UpdateTabs()
+}
+
+func (lay *Tabbed) Unplace() {
+	lay.bar.Hide()
+}
+
+func (lay *Tabbed) Remove(c Client) {
+	lay.ordered.Remove(c)
+	if lay.active == c {
+		lay.active = nil
+	}
+}
+
+// UpdateTabs redraws the tab bar. It should be called whenever focus
+// changes or the name of a client changes.
+func (lay *Tabbed) UpdateTabs() {
+	if lay.geom == nil || lay.barGeom == nil || len(lay.clients) == 0 {
+		lay.bar.Hide()
+		return
+	}
+	lay.bar.Update(lay.barGeom, lay.clients, lay.activeIndex())
+}
+
+// There is no master area in a tabbed layout.
+func (lay *Tabbed) ResizeMaster(amount float64) {
+}
+
+// There is no master area in a tabbed layout.
+func (lay *Tabbed) MastersMore() {
+}
+
+// There is no master area in a tabbed layout.
+func (lay *Tabbed) MastersFewer() {
+}
+
+// activeIndex returns the index of the focused client. If no client in this
+// layout has focus, the index of the last one that did is returned.
+func (lay *Tabbed) activeIndex() int {
+	if i := lay.current(); i > -1 {
+		lay.active = lay.clients[i]
+		return i
+	}
+	if lay.active != nil {
+		return lay.index(lay.active)
+	}
+	return -1
+}
+
+func (lay *Tabbed) rects(geom xrect.Rect, gap int) []xrect.Rect {
+	h := lay.bar.Height()
+	lay.barGeom = xrect.New(geom.X(), geom.Y(), geom.Width(), h)
+
+	r := xrect.New(geom.X(), geom.Y()+h, geom.Width(), geom.Height()-h)
+	rects := make([]xrect.Rect, len(lay.clients))
+	for i := range rects {
+		rects[i] = r
+	}
+	return rects
+}
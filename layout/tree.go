@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/BurntSushi/xgb/xproto"
+
 	"github.com/BurntSushi/xgbutil/xrect"
 
 	"github.com/xuanmingyi/wingo/misc"
@@ -27,22 +29,26 @@ func (p1 proportion) equal(p2 proportion) bool {
 
 type tree struct {
 	child node
+	gaps  Gaps
 }
 
 type node interface {
-	MoveResize(x, y, width, height int)
+	MoveResize(x, y, width, height, gap int)
 	Proportion() proportion
 	SetProportion(p proportion)
 	Parent() node
 	SetParent(n node)
-	ValidDims(w, h, minw, minh, maxw, maxh int) bool
+	ValidDims(w, h, gap, minw, minh, maxw, maxh int) bool
 	VisitLeafNodes(f func(lf *leaf) bool) bool
 }
 
 type splitter interface {
 	node
 	AddNode(n node, last bool)
+	InsertNode(n node, i int)
 	RemoveNode(n node)
+	ReplaceNode(old, n node)
+	SwapNodes(i, j int)
 	SetChildProportion(n node, newProp proportion)
 	Size() int
 	Child(i int) node
@@ -85,11 +91,18 @@ func (t *tree) place(geom xrect.Rect) bool {
 		return false
 	}
 
+	tiled := 0
+	t.child.VisitLeafNodes(func(lf *leaf) bool {
+		tiled++
+		return true
+	})
+	geom, gap := t.gaps.apply(geom, tiled)
+
 	x, y, w, h := geom.X(), geom.Y(), geom.Width(), geom.Height()
-	if !t.child.ValidDims(w, h, 1, 1, w, h) {
+	if !t.child.ValidDims(w, h, gap, 1, 1, w, h) {
 		return false
 	}
-	t.child.MoveResize(x, y, w, h)
+	t.child.MoveResize(x, y, w, h, gap)
 	return true
 }
 
@@ -97,6 +110,38 @@ func (t *tree) setChild(n node) {
 	t.child = n
 }
 
+// leaves returns every leaf in the tree, in order.
+func (t *tree) leaves() []*leaf {
+	leaves := make([]*leaf, 0)
+	if t.child == nil {
+		return leaves
+	}
+	t.child.VisitLeafNodes(func(visit *leaf) bool {
+		leaves = append(leaves, visit)
+		return true
+	})
+	return leaves
+}
+
+// clients returns the client of every leaf in the tree, in order.
+func (t *tree) clients() []Client {
+	leaves := t.leaves()
+	clients := make([]Client, len(leaves))
+	for i, lf := range leaves {
+		clients[i] = lf.client
+	}
+	return clients
+}
+
+// setClientOrder moves clients between leaves so that the clients with the
+// window ids in order come first. The shape of the tree doesn't change.
+func (t *tree) setClientOrder(order []xproto.Window) {
+	clients := reorder(t.clients(), order)
+	for i, lf := range t.leaves() {
+		lf.client = clients[i]
+	}
+}
+
 func (t *tree) switchClients(lf1, lf2 *leaf) {
 	if lf1 == nil || lf2 == nil {
 		return
@@ -182,6 +227,17 @@ func (s *split) VisitLeafNodes(f func(lf *leaf) bool) bool {
 }
 
 func (s *split) AddNode(n node, last bool) {
+	if last {
+		s.InsertNode(n, len(s.children))
+	} else {
+		s.InsertNode(n, 0)
+	}
+}
+
+// InsertNode adds a node to the split so that it becomes the i'th child.
+// The new node gets an even share of the split, which is taken from each
+// of its siblings.
+func (s *split) InsertNode(n node, i int) {
 	// Get the proportion of the new leaf.
 	newProp := fullPortion / proportion(len(s.children)+1)
 
@@ -196,11 +252,9 @@ func (s *split) AddNode(n node, last bool) {
 
 	n.SetProportion(newProp)
 
-	if last {
-		s.children = append(s.children, n)
-	} else {
-		s.children = append([]node{n}, s.children...)
-	}
+	s.children = append(s.children, nil)
+	copy(s.children[i+1:], s.children[i:])
+	s.children[i] = n
 
 	s.checkPortions()
 }
@@ -236,6 +290,22 @@ func (s *split) RemoveNode(n node) {
 	}
 }
 
+// ReplaceNode puts n in the place of old, giving it old's proportion.
+func (s *split) ReplaceNode(old, n node) {
+	i := s.ChildIndex(old)
+	if i == -1 {
+		panic(fmt.Sprintf("The node '%v' is not in the split '%v'.", old, s))
+	}
+	n.SetProportion(old.Proportion())
+	s.children[i] = n
+}
+
+// SwapNodes switches the positions of the i'th and j'th children. Each child
+// keeps its own proportion.
+func (s *split) SwapNodes(i, j int) {
+	s.children[i], s.children[j] = s.children[j], s.children[i]
+}
+
 func (s *split) SetChildProportion(n node, newProp proportion) {
 	// Find the difference between the old proportion and the new. Then
 	// spread the difference over the node's siblings.
@@ -300,49 +370,62 @@ func (s *split) PropsClear() {
 	s.saved = s.saved[:0]
 }
 
-func (hs *hsplit) MoveResize(x, y, width, height int) {
+// gapless returns the size left for the children of a split once the gaps
+// between them are taken out.
+func (s *split) gapless(size, gap int) int {
+	if len(s.children) == 0 {
+		return size
+	}
+	return size - gap*(len(s.children)-1)
+}
+
+func (hs *hsplit) MoveResize(x, y, width, height, gap int) {
 	// In hsplits, y and height remain constant. Width varies based on the
 	// proportion, and x is derived from width.
+	width = hs.gapless(width, gap)
 	nextx := x
 	for _, child := range hs.children {
 		w := child.Proportion().portion(width)
-		child.MoveResize(nextx, y, w, height)
-		nextx += w
+		child.MoveResize(nextx, y, w, height, gap)
+		nextx += w + gap
 	}
 }
 
-func (hs *hsplit) ValidDims(w, h, minw, minh, maxw, maxh int) bool {
+func (hs *hsplit) ValidDims(w, h, gap, minw, minh, maxw, maxh int) bool {
+	w = hs.gapless(w, gap)
 	for _, child := range hs.children {
 		childw := child.Proportion().portion(w)
-		if !child.ValidDims(childw, h, minw, minh, maxw, maxh) {
+		if !child.ValidDims(childw, h, gap, minw, minh, maxw, maxh) {
 			return false
 		}
 	}
 	return true
 }
 
-func (vs *vsplit) MoveResize(x, y, width, height int) {
+func (vs *vsplit) MoveResize(x, y, width, height, gap int) {
 	// In vsplits, x and width remain constant. Height varies based on the
 	// proportion, and y is derived from height.
+	height = vs.gapless(height, gap)
 	nexty := y
 	for _, child := range vs.children {
 		h := child.Proportion().portion(height)
-		child.MoveResize(x, nexty, width, h)
-		nexty += h
+		child.MoveResize(x, nexty, width, h, gap)
+		nexty += h + gap
 	}
 }
 
-func (vs *vsplit) ValidDims(w, h, minw, minh, maxw, maxh int) bool {
+func (vs *vsplit) ValidDims(w, h, gap, minw, minh, maxw, maxh int) bool {
+	h = vs.gapless(h, gap)
 	for _, child := range vs.children {
 		childh := child.Proportion().portion(h)
-		if !child.ValidDims(w, childh, minw, minh, maxw, maxh) {
+		if !child.ValidDims(w, childh, gap, minw, minh, maxw, maxh) {
 			return false
 		}
 	}
 	return true
 }
 
-func (lf *leaf) MoveResize(x, y, width, height int) {
+func (lf *leaf) MoveResize(x, y, width, height, gap int) {
 	lf.client.FrameTile()
 	lf.client.MoveResize(x, y, width, height)
 }
@@ -363,7 +446,7 @@ func (lf *leaf) SetParent(n node) {
 	lf.parent = n.(splitter)
 }
 
-func (lf *leaf) ValidDims(w, h, minw, minh, maxw, maxh int) bool {
+func (lf *leaf) ValidDims(w, h, gap, minw, minh, maxw, maxh int) bool {
 	return w >= minw && h >= minh && w <= maxw && h <= maxh
 }
 
@@ -10,6 +10,7 @@ type verthorz struct {
 	store                 *tree
 	root, masters, slaves splitter
 	allowedMasters        int
+	masterProp            proportion
 	geom                  xrect.Rect
 }
 
@@ -29,6 +30,7 @@ func NewVertical() *Vertical {
 		masters:        newVSplit(t.child),
 		slaves:         newVSplit(t.child),
 		allowedMasters: 1,
+		masterProp:     0.5,
 	}}
 
 	lay.root.SetProportion(fullPortion)
@@ -47,6 +49,7 @@ func NewHorizontal() *Horizontal {
 		masters:        newHSplit(t.child),
 		slaves:         newHSplit(t.child),
 		allowedMasters: 1,
+		masterProp:     0.5,
 	}}
 
 	lay.root.SetProportion(fullPortion)
@@ -82,7 +85,7 @@ func (lay verthorz) Exists(c Client) bool {
 	return lay.store.findLeaf(c) != nil
 }
 
-func (lay verthorz) ResizeMaster(amount float64) {
+func (lay *verthorz) ResizeMaster(amount float64) {
 	if lay.root.Size() == 2 {
 		lay.root.PropsSave()
 
@@ -91,6 +94,7 @@ func (lay verthorz) ResizeMaster(amount float64) {
 
 		if lay.store.place(lay.geom) {
 			lay.root.PropsClear()
+			lay.masterProp = lay.masters.Proportion()
 		} else {
 			lay.root.PropsRollback()
 		}
@@ -204,6 +208,51 @@ func (lay *verthorz) MastersMore() {
 	lay.Place()
 }
 
+func (lay *verthorz) SetGaps(gaps Gaps) {
+	lay.store.gaps = gaps
+}
+
+func (lay *verthorz) State() State {
+	leaves := lay.store.leaves()
+	s := State{
+		Masters:          lay.allowedMasters,
+		MasterProportion: float64(lay.masterProp),
+		Proportions:      make([]float64, len(leaves)),
+		Clients:          clientIds(lay.store.clients()),
+	}
+	for i, lf := range leaves {
+		s.Proportions[i] = float64(lf.Proportion())
+	}
+	return s
+}
+
+func (lay *verthorz) SetState(s State) {
+	if s.Masters >= 0 {
+		lay.allowedMasters = s.Masters
+	}
+	// Each adjustment moves at most one client between the masters and the
+	// slaves.
+	for i := lay.masters.Size() + lay.slaves.Size(); i >= 0; i-- {
+		lay.adjustMasters()
+	}
+	lay.adjustSplits()
+
+	if s.MasterProportion > epsilon && s.MasterProportion < 1-epsilon {
+		lay.masterProp = proportion(s.MasterProportion)
+		if lay.root.Size() == 2 {
+			lay.root.SetChildProportion(lay.masters, lay.masterProp)
+		}
+	}
+
+	lay.store.setClientOrder(s.Clients)
+	if len(s.Proportions) == lay.masters.Size()+lay.slaves.Size() {
+		// The masters always come before the slaves.
+		nm := lay.masters.Size()
+		setProportions(lay.masters, s.Proportions[:nm])
+		setProportions(lay.slaves, s.Proportions[nm:])
+	}
+}
+
 func (lay *verthorz) MastersFewer() {
 	if lay.allowedMasters == 0 {
 		return
@@ -353,6 +402,12 @@ func (lay verthorz) adjustSplits() {
 		panic(fmt.Sprintf("Unknown state. len(masters) = %d, len(slaves) = %d",
 			lay.masters.Size(), lay.slaves.Size()))
 	}
+
+	// A split that was just added gets an even share of the root, so make
+	// sure the masters keep the size they had before.
+	if lay.root.Size() == 2 && !lay.masters.Proportion().equal(lay.masterProp) {
+		lay.root.SetChildProportion(lay.masters, lay.masterProp)
+	}
 }
 
 func (lay verthorz) MROpt(c Client, flags, x, y, width, height int) {}
@@ -31,6 +31,7 @@ import (
 	"github.com/xuanmingyi/wingo/hook"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/misc"
+	"github.com/xuanmingyi/wingo/rules"
 	"github.com/xuanmingyi/wingo/stack"
 	"github.com/xuanmingyi/wingo/wm"
 	"github.com/xuanmingyi/wingo/xclient"
@@ -143,7 +144,12 @@ func main() {
 	stack.Initialize(X)
 	cursors.Initialize(X)
 	wm.Initialize(X, commands.Env, newHacks())
-	hook.Initialize(commands.Env, misc.ConfigFile("hooks.wini"))
+	hook.Initialize(commands.Env, commands.RunOnMainLoop,
+		misc.ConfigFile("hooks.wini"))
+
+	// A missing rules file just means that there are no rules.
+	rulesPath, _ := misc.ConfigPaths.ConfigFile("rules.wini")
+	rules.Initialize(rulesPath)
 
 	// Initialize event handlers on the root window.
 	rootInit(X)
@@ -155,12 +161,21 @@ func main() {
 	go ipc(X)
 
 	// And start up the IPC event notifier.
+	event.SnapshotFunc = eventSnapshot
 	go event.Notifier(X, socketFilePath(X))
 
+	// Load the saved session (if any) so that clients can be put back where
+	// they were when they are managed.
+	xclient.SessionLoadStartup()
+
 	// Just before starting the main event loop, check to see if there are
 	// any clients that already exist that we should manage.
 	manageExistingClients()
 
+	// Now that every client is in its layouts, the saved order of clients
+	// in each tiling layout can be restored.
+	xclient.SessionRestoreLayouts()
+
 	// Now make sure that clients are in the appropriate visible state.
 	for _, wrk := range wm.Heads.Workspaces.Wrks {
 		if wrk.IsVisible() {
@@ -197,11 +212,13 @@ EVENTLOOP:
 			// Wait for the event to finish processing.
 			<-pingAfter
 		case f := <-commands.SafeExec:
-			commands.SafeReturn <- f()
+			f()
 		case <-pingQuit:
 			break EVENTLOOP
 		}
 	}
+	xclient.SessionSaveShutdown()
+	wm.CloseRegister()
 	if wm.Restart {
 		event.Notify(event.Restarting{})
 		for _, client := range wm.Clients {
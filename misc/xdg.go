@@ -3,6 +3,7 @@ package misc
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
 
 	"github.com/BurntSushi/xdg"
@@ -69,3 +70,27 @@ func ScriptConfigPath(name string) string {
 	}
 	return fp
 }
+
+// UserDataFile returns the path of the file name inside the user's writable
+// Wingo data directory: $XDG_DATA_HOME/wingo, or $HOME/.local/share/wingo
+// when XDG_DATA_HOME is not set. The directory is created if necessary.
+//
+// If name is already an absolute path, it is returned unchanged.
+func UserDataFile(name string) (string, error) {
+	if path.IsAbs(name) {
+		return name, nil
+	}
+
+	var dir string
+	xdgHome := os.Getenv("XDG_DATA_HOME")
+	if len(xdgHome) > 0 && path.IsAbs(xdgHome) {
+		dir = path.Join(xdgHome, DataPaths.XDGSuffix)
+	} else {
+		dir = path.Join(os.Getenv("HOME"), ".local", "share",
+			DataPaths.XDGSuffix)
+	}
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return "", err
+	}
+	return path.Join(dir, name), nil
+}
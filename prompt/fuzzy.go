@@ -0,0 +1,136 @@
+package prompt
+
+import (
+	"unicode"
+)
+
+// The scores used by fuzzyScore. Every matched character is worth
+// fuzzyMatch, plus a bonus if it starts a word or continues a run of
+// matched characters. Skipping characters between two matched characters
+// costs a penalty for the gap, plus a small penalty for each character
+// skipped.
+const (
+	fuzzyMatch       = 16
+	fuzzyStart       = 10
+	fuzzyBoundary    = 8
+	fuzzyCamel       = 7
+	fuzzyConsecutive = 8
+	fuzzyGapStart    = 3
+	fuzzyGapExtend   = 1
+
+	// fuzzyRecency is the bonus given to the first item of a group. The
+	// following items get a smaller bonus, which breaks ties in favor of
+	// the items listed first. (i.e., the most recently focused clients.)
+	fuzzyRecency = 12
+)
+
+// fuzzyScore returns how well needle matches text as a subsequence, and the
+// positions (in runes) of the characters of text that were matched. The
+// comparison is case insensitive and spaces in needle are ignored. False is
+// returned if needle doesn't match text.
+//
+// The best match is found, not just the first one. For example, "ca" matches
+// the start of "calculator" in "archive calculator", rather than the "c" of
+// "archive".
+func fuzzyScore(text, needle []rune) (int, []int, bool) {
+	pattern := make([]rune, 0, len(needle))
+	for _, r := range needle {
+		if r != ' ' {
+			pattern = append(pattern, unicode.ToLower(r))
+		}
+	}
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	lower := make([]rune, len(text))
+	bonus := make([]int, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+		bonus[i] = fuzzyBonus(text, i)
+	}
+
+	// best[j][i] is the best score of matching pattern[:j+1] with pattern[j]
+	// matched at text[i], and from[j][i] is where pattern[j-1] is matched in
+	// that case.
+	const none = -1 << 30
+	n, m := len(text), len(pattern)
+	best, from := make([][]int, m), make([][]int, m)
+	for j := range best {
+		best[j], from[j] = make([]int, n), make([]int, n)
+	}
+	for j := 0; j < m; j++ {
+		// The best value of best[j-1][k] + fuzzyGapExtend*k for k < i-1, so
+		// that the best match with a gap before i is found in one pass.
+		gapBest, gapFrom := none, -1
+		for i := 0; i < n; i++ {
+			best[j][i], from[j][i] = none, -1
+			if j > 0 && i >= 2 && best[j-1][i-2] > none {
+				if v := best[j-1][i-2] + fuzzyGapExtend*(i-2); v > gapBest {
+					gapBest, gapFrom = v, i-2
+				}
+			}
+			if lower[i] != pattern[j] {
+				continue
+			}
+
+			score := fuzzyMatch + bonus[i]
+			if j == 0 {
+				best[j][i] = score
+				continue
+			}
+			if i >= 1 && best[j-1][i-1] > none {
+				best[j][i] = score + best[j-1][i-1] + fuzzyConsecutive
+				from[j][i] = i - 1
+			}
+			if gapFrom > -1 {
+				v := score + gapBest - fuzzyGapStart - fuzzyGapExtend*(i-2)
+				if v > best[j][i] {
+					best[j][i], from[j][i] = v, gapFrom
+				}
+			}
+		}
+	}
+
+	end, score := -1, none
+	for i := 0; i < n; i++ {
+		if best[m-1][i] > score {
+			end, score = i, best[m-1][i]
+		}
+	}
+	if end == -1 {
+		return 0, nil, false
+	}
+
+	matched := make([]int, m)
+	for j, i := m-1, end; j >= 0; j-- {
+		matched[j] = i
+		i = from[j][i]
+	}
+	return score, matched, true
+}
+
+// fuzzyBonus returns the bonus for matching the character at position i of
+// text: at the start of text, at the start of a word or at a lower to upper
+// case transition.
+func fuzzyBonus(text []rune, i int) int {
+	if i == 0 {
+		return fuzzyStart
+	}
+	prev, cur := text[i-1], text[i]
+	switch {
+	case unicode.IsSpace(prev) || unicode.IsPunct(prev):
+		return fuzzyBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return fuzzyCamel
+	case !unicode.IsDigit(prev) && unicode.IsDigit(cur):
+		return fuzzyBoundary
+	}
+	return 0
+}
+
+// fuzzyRecencyBonus returns the bonus for the item at position index in its
+// group.
+func fuzzyRecencyBonus(index int) int {
+	return fuzzyRecency / (index + 1)
+}
@@ -0,0 +1,95 @@
+package prompt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		text, needle string
+		matched      []int
+		ok           bool
+	}{
+		// The start of a word is preferred to a match in the middle of one.
+		{"archive calculator", "ca", []int{8, 9}, true},
+		{"archive calculator", "chi", []int{2, 3, 4}, true},
+		{"FireFox", "ff", []int{0, 4}, true},
+		{"Firefox", "FOX", []int{4, 5, 6}, true},
+		{"web browser", "w b", []int{0, 4}, true},
+		{"terminal", "", nil, true},
+
+		// Positions are counted in runes, not bytes.
+		{"éditeur à gauche", "àg", []int{8, 10}, true},
+		{"日本語 テキスト", "テス", []int{4, 6}, true},
+		{"Ärger über Öl", "üö", []int{6, 11}, true},
+
+		{"archive calculator", "cx", nil, false},
+		{"firefox", "fox fire", nil, false},
+		{"", "a", nil, false},
+	}
+	for _, test := range tests {
+		_, matched, ok := fuzzyScore([]rune(test.text), []rune(test.needle))
+		if ok != test.ok {
+			t.Errorf("Matching %q in %q: expected %v but got %v.",
+				test.needle, test.text, test.ok, ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(matched, test.matched) {
+			t.Errorf("Matching %q in %q: expected positions %v but got %v.",
+				test.needle, test.text, test.matched, matched)
+		}
+	}
+}
+
+func TestFuzzyScoreOrder(t *testing.T) {
+	// Each needle should score higher on the first text than on the second.
+	tests := []struct {
+		needle, better, worse string
+	}{
+		{"ca", "calculator", "arcade"},
+		{"ca", "archive calculator", "arcade"},
+		{"fb", "FooBar", "foobar"},
+		{"term", "terminal", "xterm"},
+		{"ab", "ab", "a b"},
+	}
+	for _, test := range tests {
+		better, _, ok1 := fuzzyScore([]rune(test.better), []rune(test.needle))
+		worse, _, ok2 := fuzzyScore([]rune(test.worse), []rune(test.needle))
+		if !ok1 || !ok2 {
+			t.Errorf("Expected %q to match both %q and %q.",
+				test.needle, test.better, test.worse)
+			continue
+		}
+		if better <= worse {
+			t.Errorf("Expected %q to score higher on %q (%d) than on %q "+
+				"(%d).", test.needle, test.better, better, test.worse, worse)
+		}
+	}
+}
+
+func TestFuzzyBonus(t *testing.T) {
+	tests := []struct {
+		text     string
+		i        int
+		expected int
+	}{
+		{"archive calculator", 0, fuzzyStart},
+		{"archive calculator", 8, fuzzyBoundary},
+		{"archive calculator", 9, 0},
+		{"archive calculator", 4, 0},
+		{"web-browser", 4, fuzzyBoundary},
+		{"FireFox", 4, fuzzyCamel},
+		{"Firefox", 4, 0},
+		{"xterm2", 5, fuzzyBoundary},
+		{"über Öl", 5, fuzzyBoundary},
+		{"überÖl", 4, fuzzyCamel},
+	}
+	for _, test := range tests {
+		bonus := fuzzyBonus([]rune(test.text), test.i)
+		if bonus != test.expected {
+			t.Errorf("Bonus at %d of %q: expected %d but got %d.",
+				test.i, test.text, test.expected, bonus)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package prompt
 
 import (
 	"bytes"
+	"fmt"
 	"image/color"
 
 	"github.com/BurntSushi/freetype-go/freetype/truetype"
@@ -25,9 +26,24 @@ type Input struct {
 	theme  *InputTheme
 	config InputConfig
 
-	history      []string
+	// The history used when none is given to ShowHistory, the history in
+	// use and the position in it while moving through it with Up and Down.
+	defHistory   *InputHistory
+	history      *InputHistory
 	historyIndex int
 
+	// State of a reverse incremental search through the history. (Started
+	// with Control-r.) searchIndex is the entry that matches search, and
+	// beforeSearch is the text that was in the input box when the search
+	// started, which is restored if the search is canceled.
+	searching    bool
+	search       []rune
+	searchIndex  int
+	beforeSearch string
+
+	workarea  xrect.Rect
+	labelText string
+
 	showing  bool
 	do       func(inp *Input, text string)
 	canceled func(inp *Input)
@@ -45,7 +61,8 @@ func NewInput(X *xgbutil.XUtil, theme *InputTheme, config InputConfig) *Input {
 		config:  config,
 		showing: false,
 		do:      nil,
-		history: make([]string, 0, 100),
+
+		defHistory: NewInputHistory(nil, 100),
 	}
 
 	// Create all windows used for the base of the input prompt.
@@ -54,7 +71,14 @@ func NewInput(X *xgbutil.XUtil, theme *InputTheme, config InputConfig) *Input {
 	}
 	input.win = cwin(X.RootWin())
 	input.label = cwin(input.win.Id)
-	input.input = text.NewInput(X, input.win.Id, 1000, theme.Padding,
+
+	// The input box is as wide as the part of the prompt to the right of the
+	// label, so that the text scrolls to keep the cursor visible.
+	inputWidth := theme.InputWidth - theme.BorderSize - 2*theme.Padding
+	if inputWidth < 1 {
+		inputWidth = 1
+	}
+	input.input = text.NewInput(X, input.win.Id, inputWidth, theme.Padding,
 		theme.Font, theme.FontSize, theme.FontColor, theme.BgColor)
 	input.bInp = cwin(input.win.Id)
 	input.bTop, input.bBot = cwin(input.win.Id), cwin(input.win.Id)
@@ -64,7 +88,7 @@ func NewInput(X *xgbutil.XUtil, theme *InputTheme, config InputConfig) *Input {
 	// doesn't mess with us.
 	input.win.Change(xproto.CwOverrideRedirect, 1)
 	input.win.Listen(xproto.EventMaskFocusChange)
-	input.input.Listen(xproto.EventMaskKeyPress)
+	input.input.Listen(xproto.EventMaskKeyPress | xproto.EventMaskButtonPress)
 
 	// Colorize the windows.
 	cclr := func(w *xwindow.Window, clr render.Color) {
@@ -114,9 +138,24 @@ func (inp *Input) Id() xproto.Window {
 	return inp.win.Id
 }
 
+// Show shows the input prompt with the label given, using the history of
+// the prompt itself. See ShowHistory.
 func (inp *Input) Show(workarea xrect.Rect, label string,
 	do func(inp *Input, text string), canceled func(inp *Input)) bool {
 
+	return inp.ShowHistory(workarea, label, inp.defHistory, do, canceled)
+}
+
+// ShowHistory shows the input prompt with the label given. The Up and Down
+// keys move through history, and Control-r starts a reverse incremental
+// search through it. When the confirm key is pressed, the text is added to
+// history and do is called with it. canceled is called when the prompt is
+// canceled or loses focus. If history is nil, the history of the prompt
+// itself is used.
+func (inp *Input) ShowHistory(workarea xrect.Rect, label string,
+	history *InputHistory,
+	do func(inp *Input, text string), canceled func(inp *Input)) bool {
+
 	if inp.showing {
 		return false
 	}
@@ -124,9 +163,31 @@ func (inp *Input) Show(workarea xrect.Rect, label string,
 	inp.win.Stack(xproto.StackModeAbove)
 	inp.input.Reset()
 
+	inp.workarea = workarea
+	inp.labelText = label
+	inp.place(label)
+
+	inp.showing = true
+	inp.do = do
+	inp.canceled = canceled
+	inp.win.Map()
+	inp.input.Focus()
+	if history == nil {
+		history = inp.defHistory
+	}
+	inp.history = history
+	inp.historyIndex = history.Len()
+	inp.searching = false
+
+	return true
+}
+
+// place draws the label given and sizes and centers the prompt around it.
+func (inp *Input) place(label string) {
 	text.DrawText(inp.label, inp.theme.Font, inp.theme.FontSize,
 		inp.theme.FontColor, inp.theme.BgColor, label)
 
+	workarea := inp.workarea
 	pad, bs := inp.theme.Padding, inp.theme.BorderSize
 	width := (pad * 2) + (bs * 2) +
 		inp.label.Geom.Width() + inp.theme.InputWidth
@@ -145,15 +206,6 @@ func (inp *Input) Show(workarea xrect.Rect, label string,
 	inp.bLft.Resize(bs, height)
 	inp.bRht.MoveResize(width-bs, 0, bs, height)
 	inp.input.Move(inp.bInp.Geom.X()+inp.bInp.Geom.Width(), bs)
-
-	inp.showing = true
-	inp.do = do
-	inp.canceled = canceled
-	inp.win.Map()
-	inp.input.Focus()
-	inp.historyIndex = len(inp.history)
-
-	return true
 }
 
 func (inp *Input) Hide() {
@@ -165,6 +217,7 @@ func (inp *Input) Hide() {
 	inp.input.Reset()
 
 	inp.showing = false
+	inp.searching = false
 	inp.do = nil
 	inp.canceled = nil
 }
@@ -188,32 +241,36 @@ func (inp *Input) keyResponse() xevent.KeyPressFun {
 		}
 
 		mods, kc := keybind.DeduceKeyInfo(ev.State, ev.Detail)
+		if inp.searching && inp.searchKeyResponse(mods, kc) {
+			return
+		}
 		switch {
+		case mods&xproto.ModMaskControl > 0 &&
+			keybind.KeyMatch(X, "r", mods, kc):
+
+			inp.searchStart()
 		case keybind.KeyMatch(X, "Up", mods, kc):
 			if inp.historyIndex > 0 {
 				inp.historyIndex--
-				inp.input.SetString(inp.history[inp.historyIndex])
+				inp.input.SetString(inp.history.Get(inp.historyIndex))
 			}
 		case keybind.KeyMatch(X, "Down", mods, kc):
-			if inp.historyIndex < len(inp.history) {
+			if inp.historyIndex < inp.history.Len() {
 				inp.historyIndex++
-				if inp.historyIndex < len(inp.history) {
-					inp.input.SetString(inp.history[inp.historyIndex])
+				if inp.historyIndex < inp.history.Len() {
+					inp.input.SetString(inp.history.Get(inp.historyIndex))
 				} else {
 					inp.input.Reset()
 				}
 			}
+		case inp.input.Edit(mods, kc):
+			// The cursor was moved or the text was edited.
 		case keybind.KeyMatch(X, inp.config.BackspaceKey, mods, kc):
 			inp.input.Remove()
 		case keybind.KeyMatch(X, inp.config.ConfirmKey, mods, kc):
 			if inp.do != nil {
 				s := string(inp.input.Text)
-				histLen := len(inp.history)
-
-				// Don't added repeated entries.
-				if histLen == 0 || s != inp.history[histLen-1] {
-					inp.history = append(inp.history, s)
-				}
+				inp.history.Add(s)
 				inp.do(inp, s)
 			}
 		case keybind.KeyMatch(X, inp.config.CancelKey, mods, kc):
@@ -228,6 +285,95 @@ func (inp *Input) keyResponse() xevent.KeyPressFun {
 	return xevent.KeyPressFun(f)
 }
 
+// searchIgnoreMods are the modifiers that keep a key press from being added
+// to the search string.
+const searchIgnoreMods = xproto.ModMaskControl | xproto.ModMask1 |
+	xproto.ModMask4
+
+// searchKeyResponse handles a key press during a reverse incremental search.
+// It returns false when the key press should be handled as usual, after the
+// search has ended. (i.e., the confirm key chooses the entry found.)
+func (inp *Input) searchKeyResponse(mods uint16, kc xproto.Keycode) bool {
+	X := inp.X
+	switch {
+	case mods&xproto.ModMaskControl > 0 && keybind.KeyMatch(X, "r", mods, kc):
+		// Find the next older match.
+		if inp.searchIndex > 0 {
+			inp.searchFrom(inp.searchIndex - 1)
+		}
+	case keybind.KeyMatch(X, inp.config.BackspaceKey, mods, kc):
+		if len(inp.search) > 0 {
+			inp.search = inp.search[:len(inp.search)-1]
+		}
+		inp.searchFrom(inp.history.Len() - 1)
+	case keybind.KeyMatch(X, inp.config.CancelKey, mods, kc):
+		inp.searchEnd()
+		inp.input.SetString(inp.beforeSearch)
+	case keybind.KeyMatch(X, inp.config.ConfirmKey, mods, kc),
+		keybind.KeyMatch(X, "Up", mods, kc),
+		keybind.KeyMatch(X, "Down", mods, kc):
+
+		inp.searchEnd()
+		return false
+	case mods&searchIgnoreMods > 0:
+		// Keys pressed with Control, Alt or Super don't type anything.
+	default:
+		char := text.LookupRune(X, mods, kc)
+		if char == 0 {
+			return true
+		}
+		inp.search = append(inp.search, char)
+
+		// The current match is kept as long as it still matches.
+		start := inp.searchIndex
+		if start < 0 {
+			start = inp.history.Len() - 1
+		}
+		inp.searchFrom(start)
+	}
+	return true
+}
+
+// searchStart starts a reverse incremental search through the history.
+func (inp *Input) searchStart() {
+	inp.searching = true
+	inp.search = nil
+	inp.searchIndex = -1
+	inp.beforeSearch = string(inp.input.Text)
+	inp.searchFrom(inp.history.Len() - 1)
+}
+
+// searchFrom finds the newest entry at or before index start that matches
+// the search, shows it in the input box and updates the label to show the
+// search.
+func (inp *Input) searchFrom(start int) {
+	failed := false
+	if len(inp.search) > 0 {
+		if i := inp.history.Search(string(inp.search), start); i > -1 {
+			inp.searchIndex = i
+			inp.input.SetString(inp.history.Get(i))
+		} else {
+			failed = true
+		}
+	}
+
+	label := fmt.Sprintf("(reverse-i-search)`%s':", string(inp.search))
+	if failed {
+		label = "(failed " + label[1:]
+	}
+	inp.place(label)
+}
+
+// searchEnd ends a reverse incremental search and restores the label. Moving
+// through the history with Up and Down continues from the entry found.
+func (inp *Input) searchEnd() {
+	inp.searching = false
+	if inp.searchIndex > -1 {
+		inp.historyIndex = inp.searchIndex
+	}
+	inp.place(inp.labelText)
+}
+
 type InputTheme struct {
 	BorderSize  int
 	BgColor     render.Color
@@ -0,0 +1,73 @@
+package prompt
+
+import (
+	"strings"
+)
+
+// InputHistory is the text entered in the input prompts that share it, from
+// oldest to newest. It holds at most a fixed number of entries; the oldest
+// entries are dropped first.
+//
+// An InputHistory can be passed to (*Input).ShowHistory so that unrelated
+// uses of the input prompt (say, running a command and renaming a workspace)
+// don't share the same history.
+type InputHistory struct {
+	entries []string
+	max     int
+}
+
+// NewInputHistory returns a history with the entries given (from oldest to
+// newest) that holds at most max entries.
+func NewInputHistory(entries []string, max int) *InputHistory {
+	h := &InputHistory{max: max}
+	for _, entry := range entries {
+		h.Add(entry)
+	}
+	return h
+}
+
+// Entries returns a copy of the entries of the history, from oldest to
+// newest.
+func (h *InputHistory) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
+
+// Len returns the number of entries in the history.
+func (h *InputHistory) Len() int {
+	return len(h.entries)
+}
+
+// Get returns the entry at index i, where 0 is the oldest entry.
+func (h *InputHistory) Get(i int) string {
+	return h.entries[i]
+}
+
+// Add appends s to the history, unless it is empty or the same as the newest
+// entry.
+func (h *InputHistory) Add(s string) {
+	if len(s) == 0 {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == s {
+		return
+	}
+	h.entries = append(h.entries, s)
+	if h.max > 0 && len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+}
+
+// Search returns the index of the newest entry at or before index start
+// that contains needle (case insensitive), or -1 if there is none.
+func (h *InputHistory) Search(needle string, start int) int {
+	needle = strings.ToLower(needle)
+	if start >= len(h.entries) {
+		start = len(h.entries) - 1
+	}
+	for i := start; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(h.entries[i]), needle) {
+			return i
+		}
+	}
+	return -1
+}
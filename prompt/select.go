@@ -3,6 +3,7 @@ package prompt
 import (
 	"bytes"
 	"image/color"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/freetype-go/freetype/truetype"
@@ -26,6 +27,7 @@ const (
 	TabCompletePrefix = iota
 	TabCompleteAny
 	TabCompleteMultiple
+	TabCompleteFuzzy
 )
 
 const (
@@ -46,6 +48,11 @@ type Select struct {
 	groups []*SelectShowGroup
 	items  []*SelectItem
 
+	// The most height the prompt may take, and the width and height needed
+	// to show the items that are currently shown. (Set by FilterItems.)
+	maxHeight            int
+	itemsWidth, itemsHgt int
+
 	input *text.Input
 
 	win                          *xwindow.Window
@@ -85,6 +92,7 @@ func NewSelect(X *xgbutil.XUtil,
 		slct.theme.Font, slct.theme.FontSize,
 		slct.theme.FontColor, slct.theme.BgColor)
 	slct.input.Move(slct.theme.BorderSize, slct.theme.BorderSize)
+	slct.input.OnPaste(slct.inputChanged)
 	slct.input.StackSibling(slct.bRht.Id, xproto.StackModeBelow)
 
 	// Colorize the windows.
@@ -172,12 +180,12 @@ func (slct *Select) keyResponse() xevent.KeyPressFun {
 			return
 		}
 
-		beforeLen := len(slct.input.Text)
+		before := string(slct.input.Text)
 		mods, kc := keybind.DeduceKeyInfo(ev.State, ev.Detail)
 
-		slct.input.Add(mods, kc)
-
 		switch {
+		case slct.input.Edit(mods, kc):
+			// The cursor was moved or the text was edited.
 		case keybind.KeyMatch(X, slct.config.BackspaceKey, mods, kc):
 			slct.input.Remove()
 		case keybind.KeyMatch(X, slct.config.CancelKey, mods, kc):
@@ -207,18 +215,29 @@ func (slct *Select) keyResponse() xevent.KeyPressFun {
 				slct.selected = misc.Mod(slct.selected+1, len(slct.items))
 			}
 			slct.highlight()
+		default:
+			slct.input.Add(mods, kc)
 		}
 
-		// If the length of the input changed, then re-evaluate completion
-		if beforeLen != len(slct.input.Text) {
-			slct.FilterItems(string(slct.input.Text))
-			slct.selected = -1
-			slct.highlight()
+		// If the input changed, then re-evaluate completion
+		if before != string(slct.input.Text) {
+			slct.inputChanged()
 		}
 	}
 	return xevent.KeyPressFun(f)
 }
 
+// inputChanged filters the items with the text in the input box, and selects
+// the default item.
+func (slct *Select) inputChanged() {
+	if !slct.showing {
+		return
+	}
+	slct.FilterItems(string(slct.input.Text))
+	slct.selected = slct.defaultSelection()
+	slct.highlight()
+}
+
 func (slct *Select) Show(workarea xrect.Rect, tabCompleteType int,
 	groups []*SelectShowGroup, data interface{}) bool {
 
@@ -249,7 +268,9 @@ func (slct *Select) Show(workarea xrect.Rect, tabCompleteType int,
 	slct.win.Stack(xproto.StackModeAbove)
 	slct.input.Reset()
 
-	// Position the initial list of items with no filter.
+	// Position the initial list of items with no filter. Items that don't
+	// fit in the workarea aren't shown.
+	slct.maxHeight = int(float64(workarea.Height()) * 0.8)
 	slct.FilterItems("")
 
 	// Create some short aliases and start computing the geometry of the
@@ -258,26 +279,9 @@ func (slct *Select) Show(workarea xrect.Rect, tabCompleteType int,
 	pad := slct.theme.Padding
 
 	inpHeight := slct.input.Geom.Height()
-	height := 2*(bs+pad) + inpHeight + bs
-	maxFontWidth := 0
-	didGroupSpacing := false
-	for _, group := range slct.groups {
-		if len(group.items) > 0 && group.hasGroup() {
-			maxFontWidth = misc.Max(maxFontWidth, group.win.Geom.Width())
-			height += group.win.Geom.Height() + slct.theme.GroupSpacing
-			didGroupSpacing = true
-		}
-		for _, item := range group.items {
-			maxFontWidth = misc.Max(maxFontWidth, item.regular.Geom.Width())
-			height += item.regular.Geom.Height() + itemTopSpace + itemBotSpace
-		}
-	}
-
-	if didGroupSpacing {
-		height -= slct.theme.GroupSpacing
-	}
+	height := slct.itemsHgt
 	maxWidth := int(float64(workarea.Width()) * 0.8)
-	width := misc.Min(maxWidth, maxFontWidth+2*(bs+pad))
+	width := misc.Min(maxWidth, slct.itemsWidth+2*(bs+pad))
 
 	// position the damn window based on its width/height (i.e., center it)
 	posx := workarea.X() + workarea.Width()/2 - width/2
@@ -300,58 +304,98 @@ func (slct *Select) Show(workarea xrect.Rect, tabCompleteType int,
 	return true
 }
 
+// FilterItems shows the items matching search, according to the tab
+// completion mode of the prompt. Items are shown in order until the prompt
+// would be higher than its maximum height.
+//
+// In the fuzzy mode, the items of each group are ordered by how well they
+// match search, and the groups are ordered by their best match. The matched
+// characters of each item are highlighted.
 func (slct *Select) FilterItems(search string) {
 	bs := slct.theme.BorderSize
 	pad := slct.theme.Padding
 	inpHeight := slct.input.Geom.Height()
-	needle := strings.ToLower(search)
+	needle := []rune(strings.ToLower(search))
+
+	// Find the matching items of each group first, so that they can be
+	// ordered before they are shown.
+	groups := make([]*selectMatches, len(slct.groups))
+	for i, group := range slct.groups {
+		groups[i] = &selectMatches{group: group, best: -1 << 30}
+		for j, item := range group.items {
+			m, ok := slct.match(item, needle, j)
+			if !ok {
+				item.hide()
+				continue
+			}
+			groups[i].matches = append(groups[i].matches, m)
+			if m.score > groups[i].best {
+				groups[i].best = m.score
+			}
+		}
+	}
+	if slct.tabComplete == TabCompleteFuzzy && len(needle) > 0 {
+		for _, g := range groups {
+			sort.SliceStable(g.matches, func(i, j int) bool {
+				return g.matches[i].score > g.matches[j].score
+			})
+		}
+		sort.SliceStable(groups, func(i, j int) bool {
+			return groups[i].best > groups[j].best
+		})
+	}
 
 	slct.items = make([]*SelectItem, 0)
+	slct.itemsWidth = 0
+
+	// full is true once there's no room left for more items.
+	full := false
+	fits := func(y, height int) bool {
+		return slct.maxHeight <= 0 || y+height+pad+bs <= slct.maxHeight
+	}
 
 	x, y := bs+pad, (2*bs)+pad+inpHeight
-	for _, group := range slct.groups {
+	bottom := y
+	for _, g := range groups {
+		group := g.group
 		shown := false // true when at least 1 item is showing
 
 		if group.hasGroup() {
+			if !full && !fits(y, group.win.Geom.Height()) {
+				full = true
+			}
 			group.show(x, y)
 			y += group.win.Geom.Height()
 		}
-		for _, item := range group.items {
-			haystack := strings.ToLower(item.text)
-			switch slct.tabComplete {
-			case TabCompleteAny:
-				if !strings.Contains(haystack, needle) {
-					item.hide()
-					continue
-				}
-			case TabCompleteMultiple:
-				words := strings.Fields(needle)
-				match := true
-				for _, word := range words {
-					if !strings.Contains(haystack, word) {
-						match = false
-						break
-					}
-				}
-				if !match {
-					item.hide()
-					continue
-				}
-			default:
-				if !strings.HasPrefix(haystack, needle) {
-					item.hide()
-					continue
-				}
+		for _, m := range g.matches {
+			item := m.item
+			if full {
+				item.hide()
+				continue
+			}
+
+			item.setMatched(m.matched)
+			item.create()
+			h := itemTopSpace + item.regular.Geom.Height() + itemBotSpace
+			if !fits(y, h) {
+				full = true
+				item.hide()
+				continue
 			}
 
 			y += itemTopSpace
 			item.show(x, y)
 			y += item.regular.Geom.Height() + itemBotSpace
+			bottom = y
 			slct.items = append(slct.items, item)
+			slct.itemsWidth = misc.Max(slct.itemsWidth,
+				item.regular.Geom.Width())
 			shown = true
 		}
 		if group.hasGroup() {
 			if shown {
+				slct.itemsWidth = misc.Max(slct.itemsWidth,
+					group.win.Geom.Width())
 				y += slct.theme.GroupSpacing
 			} else {
 				group.hide()
@@ -359,6 +403,65 @@ func (slct *Select) FilterItems(search string) {
 			}
 		}
 	}
+
+	// There's no spacing after the last group.
+	slct.itemsHgt = bottom + pad + bs
+}
+
+// selectMatches is the list of items of a group that match the search, and
+// the best score among them.
+type selectMatches struct {
+	group   *SelectShowGroup
+	matches []selectMatch
+	best    int
+}
+
+// selectMatch is an item that matches the search. In the fuzzy mode, score
+// is how well the item matches and matched is the positions of the
+// characters that were matched.
+type selectMatch struct {
+	item    *SelectItem
+	score   int
+	matched []int
+}
+
+// match returns whether item matches needle in the tab completion mode of
+// the prompt. needle should already be lower case. index is the position of
+// the item in its group, which breaks ties in the fuzzy mode.
+func (slct *Select) match(item *SelectItem, needle []rune,
+	index int) (selectMatch, bool) {
+
+	m := selectMatch{item: item}
+	haystack := strings.ToLower(item.text)
+	switch slct.tabComplete {
+	case TabCompleteAny:
+		return m, strings.Contains(haystack, string(needle))
+	case TabCompleteMultiple:
+		for _, word := range strings.Fields(string(needle)) {
+			if !strings.Contains(haystack, word) {
+				return m, false
+			}
+		}
+		return m, true
+	case TabCompleteFuzzy:
+		score, matched, ok := fuzzyScore([]rune(item.text), needle)
+		m.score = score + fuzzyRecencyBonus(index)
+		m.matched = matched
+		return m, ok
+	}
+	return m, strings.HasPrefix(haystack, string(needle))
+}
+
+// defaultSelection returns the item selected after the items have been
+// filtered. In the fuzzy mode, the best match is selected so that it can be
+// chosen right away. Otherwise, nothing is selected.
+func (slct *Select) defaultSelection() int {
+	if slct.tabComplete == TabCompleteFuzzy &&
+		len(slct.input.Text) > 0 && len(slct.items) > 0 {
+
+		return 0
+	}
+	return -1
 }
 
 func (slct *Select) Hide() {
@@ -402,6 +505,11 @@ type SelectTheme struct {
 	ActiveBgColor   render.Color
 	ActiveFontColor render.Color
 
+	// The colors of the characters matched by a fuzzy search, in regular and
+	// highlighted items.
+	MatchFontColor       render.Color
+	ActiveMatchFontColor render.Color
+
 	GroupBgColor   render.Color
 	GroupFont      *truetype.Font
 	GroupFontSize  float64
@@ -423,6 +531,10 @@ var DefaultSelectTheme = &SelectTheme{
 	ActiveBgColor:   render.NewImageColor(color.RGBA{0x0, 0x0, 0x0, 0xff}),
 	ActiveFontColor: render.NewImageColor(color.RGBA{0xff, 0xff, 0xff, 0xff}),
 
+	MatchFontColor: render.NewImageColor(color.RGBA{0x33, 0x66, 0xff, 0xff}),
+	ActiveMatchFontColor: render.NewImageColor(
+		color.RGBA{0x99, 0xbb, 0xff, 0xff}),
+
 	GroupBgColor: render.NewImageColor(color.RGBA{0xff, 0xff, 0xff, 0xff}),
 	GroupFont: xgraphics.MustFont(xgraphics.ParseFont(
 		bytes.NewBuffer(misc.DataFile("DejaVuSans.ttf")))),
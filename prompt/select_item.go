@@ -1,26 +1,52 @@
 package prompt
 
 import (
+	"image"
+
 	"github.com/BurntSushi/xgb/xproto"
 
+	"github.com/BurntSushi/xgbutil/xgraphics"
 	"github.com/BurntSushi/xgbutil/xwindow"
 
 	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/render"
 	"github.com/xuanmingyi/wingo/text"
 )
 
+const itemIconSpace = 8
+
 type SelectChoice interface {
 	SelectText() string
 	SelectSelected(data interface{})
 	SelectHighlighted(data interface{})
 }
 
+// SelectIconChoice is a SelectChoice with an icon, which is drawn to the left
+// of its text at the height of the text. SelectIcon should return a new
+// image every time it's called (it is destroyed once it has been drawn), or
+// nil if the choice has no icon.
+type SelectIconChoice interface {
+	SelectChoice
+	SelectIcon() *xgraphics.Image
+}
+
+// SelectItem is a representation of a SelectChoice in a select prompt.
+//
+// The windows of a SelectItem are only created (and its text and icon only
+// drawn) the first time it's shown. This makes it cheap to add thousands of
+// choices to a select prompt, of which only a few are ever shown.
 type SelectItem struct {
 	slct   *Select
 	choice SelectChoice
 
 	text                 string
 	regular, highlighted *xwindow.Window
+	visible              bool
+
+	// The positions (in runes) of the characters of text that are drawn
+	// with the match font color, i.e., the characters matched by a fuzzy
+	// search.
+	matched []int
 }
 
 func newSelectItem(slct *Select, choice SelectChoice) *SelectItem {
@@ -28,6 +54,17 @@ func newSelectItem(slct *Select, choice SelectChoice) *SelectItem {
 		slct:   slct,
 		choice: choice,
 	}
+	si.UpdateText()
+
+	return si
+}
+
+// create creates the windows of the item and draws it, if that hasn't been
+// done yet.
+func (si *SelectItem) create() {
+	if si.regular != nil {
+		return
+	}
 
 	si.regular = xwindow.Must(xwindow.Create(si.slct.X, si.slct.win.Id))
 	si.highlighted = xwindow.Must(xwindow.Create(si.slct.X, si.slct.win.Id))
@@ -36,20 +73,24 @@ func newSelectItem(slct *Select, choice SelectChoice) *SelectItem {
 	si.regular.StackSibling(si.slct.bRht.Id, xproto.StackModeBelow)
 	si.highlighted.StackSibling(si.slct.bRht.Id, xproto.StackModeBelow)
 
-	si.UpdateText()
-
-	return si
+	si.draw()
 }
 
 func (si *SelectItem) show(x, y int) {
+	si.create()
 	si.regular.Move(x, y)
 	si.highlighted.Move(x, y)
 	si.regular.Map()
+	si.visible = true
 }
 
 func (si *SelectItem) hide() {
+	if !si.visible {
+		return
+	}
 	si.regular.Unmap()
 	si.highlighted.Unmap()
+	si.visible = false
 }
 
 func (si *SelectItem) choose() {
@@ -68,12 +109,16 @@ func (si *SelectItem) unhighlight() {
 }
 
 func (si *SelectItem) Destroy() {
+	if si.regular == nil {
+		return
+	}
 	si.regular.Destroy()
 	si.highlighted.Destroy()
+	si.regular, si.highlighted = nil, nil
+	si.visible = false
 }
 
 func (si *SelectItem) UpdateText() {
-	t := si.slct.theme
 	si.text = si.choice.SelectText()
 
 	// Always have some text.
@@ -81,17 +126,126 @@ func (si *SelectItem) UpdateText() {
 		si.text = "N/A"
 	}
 
-	err := text.DrawText(si.regular, t.Font, t.FontSize,
-		t.FontColor, t.BgColor, si.text)
+	// If the item has never been shown, it is drawn when it is.
+	if si.regular != nil {
+		si.draw()
+	}
+}
+
+// setMatched changes the characters of the item that are highlighted as
+// matched, and redraws the item if it has been drawn before and they have
+// changed.
+func (si *SelectItem) setMatched(matched []int) {
+	if sameInts(si.matched, matched) {
+		return
+	}
+	si.matched = matched
+	if si.regular != nil {
+		si.draw()
+	}
+}
+
+func (si *SelectItem) draw() {
+	t := si.slct.theme
+
+	// The icon is a square as high as the text.
+	var icon *xgraphics.Image
+	if iconChoice, ok := si.choice.(SelectIconChoice); ok {
+		if icon = iconChoice.SelectIcon(); icon != nil {
+			_, eh := xgraphics.Extents(t.Font, t.FontSize, si.text)
+			icon = icon.Scale(eh, eh)
+		}
+	}
+
+	err := si.drawWindow(si.regular, icon,
+		t.FontColor, t.MatchFontColor, t.BgColor)
 	if err != nil {
 		logger.Warning.Printf("(*SelectItem).UpdateText: "+
 			"Could not render text: %s", err)
 	}
 
-	err = text.DrawText(si.highlighted, t.Font, t.FontSize,
-		t.ActiveFontColor, t.ActiveBgColor, si.text)
+	err = si.drawWindow(si.highlighted, icon,
+		t.ActiveFontColor, t.ActiveMatchFontColor, t.ActiveBgColor)
 	if err != nil {
 		logger.Warning.Printf("(*SelectItem).UpdateText: "+
 			"Could not render text: %s", err)
 	}
+
+	if icon != nil {
+		icon.Destroy()
+	}
+}
+
+// drawWindow draws the text of the item, preceded by icon if it isn't nil,
+// to win and resizes win to fit. Matched characters are drawn with matchClr.
+func (si *SelectItem) drawWindow(win *xwindow.Window, icon *xgraphics.Image,
+	fontClr, matchClr, bgClr render.Color) error {
+
+	t := si.slct.theme
+	if icon == nil && len(si.matched) == 0 {
+		return text.DrawText(win, t.Font, t.FontSize, fontClr, bgClr, si.text)
+	}
+
+	// Measure the text just like text.DrawText does, and make room for a
+	// square icon to its left.
+	ew, eh := text.Extents(t.Font, t.FontSize, si.text)
+	tx := 0
+	if icon != nil {
+		tx = eh + itemIconSpace
+	}
+
+	img := xgraphics.New(si.slct.X, image.Rect(0, 0, tx+ew, eh))
+	xgraphics.BlendBgColor(img, bgClr.ImageColor())
+	if icon != nil {
+		xgraphics.Blend(img, icon, image.ZP)
+	}
+
+	// Draw runs of characters that are either all matched or all not
+	// matched, each starting where the last one ended.
+	runes := []rune(si.text)
+	matched := make([]bool, len(runes))
+	for _, i := range si.matched {
+		if i >= 0 && i < len(runes) {
+			matched[i] = true
+		}
+	}
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		clr := fontClr
+		if matched[start] {
+			clr = matchClr
+		}
+
+		var err error
+		tx, _, err = img.Text(tx, 0, clr.ImageColor(), t.FontSize, t.Font,
+			string(runes[start:end]))
+		if err != nil {
+			img.Destroy()
+			return err
+		}
+		start = end
+	}
+
+	win.Resize(img.Bounds().Dx(), eh)
+	img.XSurfaceSet(win.Id)
+	img.XDraw()
+	img.XPaint(win.Id)
+	img.Destroy()
+
+	return nil
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
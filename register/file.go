@@ -0,0 +1,118 @@
+package register
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/xuanmingyi/wingo/errno"
+)
+
+func init() {
+	registerDriver("file", func() Driver { return new(FileDriver) })
+}
+
+// FileDriver stores all records in a single JSON file. Every record is saved
+// with its type, so values are read back with the same type they were
+// written with. The whole file is rewritten on every change.
+type FileDriver struct {
+	Path    string
+	Records map[string]typedValue
+	Valid   bool
+}
+
+func (d *FileDriver) IsValid() bool {
+	return d.Valid
+}
+
+func (d *FileDriver) Open(path string) error {
+	// path: file:///home/me/register.json
+	d.Path = path[7:]
+	d.Records = make(map[string]typedValue)
+
+	bs, err := ioutil.ReadFile(d.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(bs) > 0 {
+		if err := json.Unmarshal(bs, &d.Records); err != nil {
+			return err
+		}
+	}
+	d.Valid = true
+	return nil
+}
+
+func (d *FileDriver) Close() {
+	d.Valid = false
+}
+
+func (d *FileDriver) Create(key string, value Record) error {
+	return d.Update(key, value)
+}
+
+func (d *FileDriver) Update(key string, value Record) error {
+	if !d.IsValid() {
+		return errno.RegisterDriverNotValid
+	}
+	typ, encoded, err := encode(value)
+	if err != nil {
+		return err
+	}
+	old, existed := d.Records[key]
+	d.Records[key] = typedValue{Type: typ, Value: encoded}
+	if err := d.write(); err != nil {
+		if existed {
+			d.Records[key] = old
+		} else {
+			delete(d.Records, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (d *FileDriver) Delete(key string) error {
+	if !d.IsValid() {
+		return errno.RegisterDriverNotValid
+	}
+	old, existed := d.Records[key]
+	if !existed {
+		return nil
+	}
+	delete(d.Records, key)
+	if err := d.write(); err != nil {
+		d.Records[key] = old
+		return err
+	}
+	return nil
+}
+
+func (d *FileDriver) Search(key string) (map[string]Record, error) {
+	if !d.IsValid() {
+		return nil, errno.RegisterDriverNotValid
+	}
+	records := make(map[string]Record, len(d.Records))
+	for k, v := range d.Records {
+		record, err := decode(v.Type, v.Value)
+		if err != nil {
+			return nil, err
+		}
+		records[k] = record
+	}
+	return records, nil
+}
+
+// write saves every record to a temporary file and renames it over the
+// register file, so that a crash can't leave a half written register behind.
+func (d *FileDriver) write() error {
+	bs, err := json.MarshalIndent(d.Records, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := d.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, bs, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.Path)
+}
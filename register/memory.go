@@ -0,0 +1,67 @@
+package register
+
+import (
+	"github.com/xuanmingyi/wingo/errno"
+)
+
+func init() {
+	registerDriver("mem", func() Driver { return new(MemDriver) })
+}
+
+// MemDriver keeps records in memory only. Nothing survives a restart, which
+// makes it useful for testing or when no persistent storage is wanted.
+type MemDriver struct {
+	Records map[string]Record
+	Valid   bool
+}
+
+func (d *MemDriver) IsValid() bool {
+	return d.Valid
+}
+
+func (d *MemDriver) Open(path string) error {
+	// path: mem://
+	d.Records = make(map[string]Record)
+	d.Valid = true
+	return nil
+}
+
+func (d *MemDriver) Close() {
+	d.Records = nil
+	d.Valid = false
+}
+
+func (d *MemDriver) Create(key string, value Record) error {
+	return d.Update(key, value)
+}
+
+func (d *MemDriver) Update(key string, value Record) error {
+	if !d.IsValid() {
+		return errno.RegisterDriverNotValid
+	}
+	value, err := normalize(value)
+	if err != nil {
+		return err
+	}
+	d.Records[key] = value
+	return nil
+}
+
+func (d *MemDriver) Delete(key string) error {
+	if !d.IsValid() {
+		return errno.RegisterDriverNotValid
+	}
+	delete(d.Records, key)
+	return nil
+}
+
+func (d *MemDriver) Search(key string) (map[string]Record, error) {
+	if !d.IsValid() {
+		return nil, errno.RegisterDriverNotValid
+	}
+	records := make(map[string]Record, len(d.Records))
+	for k, v := range d.Records {
+		records[k] = v
+	}
+	return records, nil
+}
@@ -2,12 +2,23 @@ package register
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+
+	"github.com/xuanmingyi/wingo/errno"
 )
 
 type Record interface{}
 
+// 所有可用的驱动, 以 URL 协议为键.
+// 每个驱动在自己的 init 中注册.
+var drivers = map[string]func() Driver{}
+
+func registerDriver(scheme string, newDriver func() Driver) {
+	drivers[scheme] = newDriver
+}
+
 type Register struct {
 	Driver  Driver
 	Lock    sync.Mutex
@@ -18,23 +29,37 @@ func (r *Register) DefaultRecord(key string, record Record) {
 
 }
 
-func (r *Register) Open(path string) {
-	var err error
+// 打开
+// path: sqlite://my.db, file://register.json 或 mem://
+func (r *Register) Open(path string) (err error) {
+	i := strings.Index(path, "://")
+	if i == -1 {
+		return fmt.Errorf("%w: %s", errno.RegisterUnknownScheme, path)
+	}
+	newDriver, ok := drivers[path[:i]]
+	if !ok {
+		return fmt.Errorf("%w: %s", errno.RegisterUnknownScheme, path[:i])
+	}
 
-	if strings.HasPrefix(path, "sqlite://") {
-		r.Driver = new(SQliteDriver)
-		r.Driver.Open(path)
+	r.Driver = newDriver()
+	if err = r.Driver.Open(path); err != nil {
+		return err
+	}
+	if !r.Driver.IsValid() {
+		return errno.RegisterDriverNotValid
 	}
 
 	// 缓存所有数据
 	r.Records, err = r.Driver.Search("*")
 	if err != nil {
-		panic(err)
+		r.Driver.Close()
+		return err
 	}
+	return nil
 }
 
 func (r *Register) Close() {
-	if r.Driver.IsValid() {
+	if r.Driver != nil && r.Driver.IsValid() {
 		r.Driver.Close()
 	}
 }
@@ -47,7 +72,7 @@ func (r *Register) Get(key string) (record Record, err error) {
 	if record, found := r.Records[key]; found {
 		return record, nil
 	} else {
-		return nil, fmt.Errorf("key not found: %s", key)
+		return nil, fmt.Errorf("%w: %s", errno.RegisterKeyNotFound, key)
 	}
 }
 
@@ -57,6 +82,12 @@ func (r *Register) Set(key string, value Record) (err error) {
 	r.Lock.Lock()
 	defer r.Lock.Unlock()
 
+	// 统一类型, 这样缓存中的值和从驱动读出的值一致
+	value, err = normalize(value)
+	if err != nil {
+		return err
+	}
+
 	if _, found := r.Records[key]; found {
 		// found
 		err = r.Driver.Update(key, value)
@@ -75,17 +106,32 @@ func (r *Register) Set(key string, value Record) (err error) {
 	return nil
 }
 
+// 列出
+func (r *Register) Keys(prefix string) []string {
+	r.Lock.Lock()
+	defer r.Lock.Unlock()
+
+	keys := make([]string, 0, len(r.Records))
+	for key := range r.Records {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // 删除
-func (r *Register) Del(key string) {
+func (r *Register) Del(key string) error {
 	r.Lock.Lock()
 	defer r.Lock.Unlock()
 
 	if _, found := r.Records[key]; found {
 		// found
-		err := r.Driver.Delete(key)
-		if err != nil {
-			panic(err)
+		if err := r.Driver.Delete(key); err != nil {
+			return err
 		}
 	}
 	delete(r.Records, key)
+	return nil
 }
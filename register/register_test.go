@@ -1,9 +1,16 @@
 package register
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
 	"sync"
 	"testing"
+
+	"github.com/xuanmingyi/wingo/errno"
 )
 
 func RecordCount(r *Register, wg *sync.WaitGroup) {
@@ -21,14 +28,24 @@ func RecordCount(r *Register, wg *sync.WaitGroup) {
 }
 
 func TestRegister(t *testing.T) {
+	if _, ok := drivers["sqlite"]; !ok {
+		t.Skip("built without the sqlite driver")
+	}
+	dir, err := ioutil.TempDir("", "wingo-register")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
 	r := Register{}
 
-	r.Open("sqlite://a.db")
+	if err := r.Open("sqlite://" + path.Join(dir, "a.db")); err != nil {
+		panic(err)
+	}
 
 	defer r.Close()
 
 	var record Record
-	var err error
 	err = r.Set("ssss", 13)
 	if err != nil {
 		panic(err)
@@ -60,3 +77,96 @@ func TestRegister(t *testing.T) {
 		wg.Wait()
 	*/
 }
+
+// 每种类型都应该原样读回
+var typedRecords = map[string]Record{
+	"int":    42,
+	"float":  1.5,
+	"string": "browser",
+	"bool":   true,
+	"list":   []Record{1, "two", 3.5, false},
+}
+
+func checkTyped(t *testing.T, r *Register) {
+	for key, want := range typedRecords {
+		got, err := r.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %#v, want %#v", key, got, want)
+		}
+	}
+}
+
+func TestMemDriver(t *testing.T) {
+	r := Register{}
+	if err := r.Open("mem://"); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for key, record := range typedRecords {
+		if err := r.Set(key, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkTyped(t, &r)
+
+	if err := r.Del("int"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Get("int"); !errors.Is(err, errno.RegisterKeyNotFound) {
+		t.Fatalf("expected RegisterKeyNotFound, got %v", err)
+	}
+	if err := r.Set("bad", struct{}{}); !errors.Is(err, errno.RegisterUnknownType) {
+		t.Fatalf("expected RegisterUnknownType, got %v", err)
+	}
+}
+
+func TestFileDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wingo-register")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	url := "file://" + path.Join(dir, "register.json")
+
+	r := Register{}
+	if err := r.Open(url); err != nil {
+		t.Fatal(err)
+	}
+	for key, record := range typedRecords {
+		if err := r.Set(key, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Set("deleted", "soon"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Del("deleted"); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	// 重新打开, 所有值都应该从文件中读回
+	r2 := Register{}
+	if err := r2.Open(url); err != nil {
+		t.Fatal(err)
+	}
+	defer r2.Close()
+
+	checkTyped(t, &r2)
+	if _, err := r2.Get("deleted"); err == nil {
+		t.Fatal("deleted key was read back from the file")
+	}
+}
+
+func TestOpenUnknownScheme(t *testing.T) {
+	for _, url := range []string{"redis://localhost", "register.db"} {
+		r := Register{}
+		if err := r.Open(url); !errors.Is(err, errno.RegisterUnknownScheme) {
+			t.Fatalf("%s: expected RegisterUnknownScheme, got %v", url, err)
+		}
+	}
+}
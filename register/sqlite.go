@@ -1,39 +1,44 @@
+//go:build !nosqlite
+// +build !nosqlite
+
 package register
 
 import (
 	"database/sql"
-	"strconv"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/xuanmingyi/wingo/errno"
 )
 
+// 用 nosqlite 构建标签可以去掉 cgo 依赖.
+func init() {
+	registerDriver("sqlite", func() Driver { return new(SQliteDriver) })
+}
+
 type SQliteDriver struct {
 	DB    *sql.DB
 	Valid bool
 }
 
-// type:
-// 0  int
-// 1  float
-// 2  string
-func (d *SQliteDriver) init() {
+// type: 见 value.go
+func (d *SQliteDriver) init() error {
 	create_table_sqls := []string{
 		`CREATE TABLE IF NOT EXISTS "register" (
 		"id" INTEGER PRIMARY KEY AUTOINCREMENT,
 		"key" VARCHAR(128) UNIQUE,
 		"type" int(4),
-		"value" VARCHAR(128));`,
+		"value" TEXT);`,
 	}
 
 	for _, sql := range create_table_sqls {
 		_, err := d.DB.Exec(sql)
 		if err != nil {
-			return
+			return err
 		}
 	}
 
 	d.Valid = true
+	return nil
 }
 
 func (d *SQliteDriver) IsValid() bool {
@@ -46,13 +51,17 @@ func (d *SQliteDriver) Open(path string) (err error) {
 	if err != nil {
 		return err
 	}
-	d.init()
+	if err = d.init(); err != nil {
+		d.DB.Close()
+		return err
+	}
 	return nil
 }
 
 func (d *SQliteDriver) Close() {
 	if d.IsValid() {
 		d.DB.Close()
+		d.Valid = false
 	}
 }
 
@@ -60,20 +69,17 @@ func (d *SQliteDriver) Create(key string, value Record) (err error) {
 	if !d.IsValid() {
 		return errno.RegisterDriverNotValid
 	}
-	var type_flag int
-	stmt, err := d.DB.Prepare("INSERT INTO `register`(key, type, value) values (?, ?, ?)")
+	type_flag, encoded, err := encode(value)
 	if err != nil {
 		return err
 	}
-	switch value.(type) {
-	case int, int64:
-		type_flag = 0
-	case float32, float64:
-		type_flag = 1
-	case string:
-		type_flag = 2
+	stmt, err := d.DB.Prepare("INSERT INTO `register`(key, type, value) values (?, ?, ?)")
+	if err != nil {
+		return err
 	}
-	_, err = stmt.Exec(key, type_flag, value)
+	defer stmt.Close()
+
+	_, err = stmt.Exec(key, type_flag, encoded)
 	if err != nil {
 		return err
 	}
@@ -85,10 +91,11 @@ func (d *SQliteDriver) Delete(key string) (err error) {
 		return errno.RegisterDriverNotValid
 	}
 
-	stmt, err := d.DB.Prepare("DELETE FROM `register_string` WHERE `key` = ?")
+	stmt, err := d.DB.Prepare("DELETE FROM `register` WHERE `key` = ?")
 	if err != nil {
 		return err
 	}
+	defer stmt.Close()
 
 	_, err = stmt.Exec(key)
 	if err != nil {
@@ -101,11 +108,17 @@ func (d *SQliteDriver) Update(key string, value Record) (err error) {
 	if !d.IsValid() {
 		return errno.RegisterDriverNotValid
 	}
-	stmt, err := d.DB.Prepare("UPDATE `register` SET `value` = ? WHERE `key` = ?")
+	type_flag, encoded, err := encode(value)
 	if err != nil {
 		return err
 	}
-	_, err = stmt.Exec(value, key)
+	stmt, err := d.DB.Prepare("UPDATE `register` SET `type` = ?, `value` = ? WHERE `key` = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(type_flag, encoded, key)
 	if err != nil {
 		return err
 	}
@@ -122,26 +135,22 @@ func (d *SQliteDriver) Search(key string) (records map[string]Record, err error)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var key string
 		var type_flag int
-		var record Record
-		err = rows.Scan(&key, &type_flag, &record)
+		var value string
+		err = rows.Scan(&key, &type_flag, &value)
 		if err != nil {
 			return nil, err
 		}
 
-		switch type_flag {
-		case 0:
-			record, _ = strconv.ParseInt(record.(string), 10, 64)
-		case 1:
-			record, _ = strconv.ParseFloat(record.(string), 64)
-		case 2:
-			record = record.(string)
+		record, err := decode(type_flag, value)
+		if err != nil {
+			return nil, err
 		}
-
 		records[key] = record
 	}
-	return records, nil
+	return records, rows.Err()
 }
@@ -0,0 +1,111 @@
+package register
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/xuanmingyi/wingo/errno"
+)
+
+// 值的类型
+// type:
+// 0  int
+// 1  float
+// 2  string
+// 3  bool
+// 4  list
+const (
+	TypeInt = iota
+	TypeFloat
+	TypeString
+	TypeBool
+	TypeList
+)
+
+// typedValue is the serialized form of a record: its type and its value
+// encoded as a string. Lists are encoded as a JSON array of typedValues.
+type typedValue struct {
+	Type  int    `json:"type"`
+	Value string `json:"value"`
+}
+
+// encode converts a record to its type and string representation.
+// Integers are always decoded as int, floats as float64 and lists as
+// []Record.
+func encode(record Record) (typ int, value string, err error) {
+	switch v := record.(type) {
+	case int:
+		return TypeInt, strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return TypeInt, strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return TypeInt, strconv.FormatInt(v, 10), nil
+	case float32:
+		return TypeFloat, strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return TypeFloat, strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		return TypeString, v, nil
+	case bool:
+		return TypeBool, strconv.FormatBool(v), nil
+	case []Record:
+		items := make([]typedValue, len(v))
+		for i, item := range v {
+			if items[i].Type, items[i].Value, err = encode(item); err != nil {
+				return 0, "", err
+			}
+		}
+		bs, err := json.Marshal(items)
+		if err != nil {
+			return 0, "", err
+		}
+		return TypeList, string(bs), nil
+	case []string:
+		list := make([]Record, len(v))
+		for i := range v {
+			list[i] = v[i]
+		}
+		return encode(list)
+	}
+	return 0, "", fmt.Errorf("%w: %T", errno.RegisterUnknownType, record)
+}
+
+// decode is the inverse of encode.
+func decode(typ int, value string) (Record, error) {
+	switch typ {
+	case TypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return int(n), err
+	case TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	case TypeString:
+		return value, nil
+	case TypeBool:
+		return strconv.ParseBool(value)
+	case TypeList:
+		var items []typedValue
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return nil, err
+		}
+		list := make([]Record, len(items))
+		for i, item := range items {
+			var err error
+			if list[i], err = decode(item.Type, item.Value); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("%w: %d", errno.RegisterUnknownType, typ)
+}
+
+// normalize round trips a record through encode and decode, so that every
+// driver hands back the same Go types no matter how the value was set.
+func normalize(record Record) (Record, error) {
+	typ, value, err := encode(record)
+	if err != nil {
+		return nil, err
+	}
+	return decode(typ, value)
+}
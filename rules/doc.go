@@ -0,0 +1,20 @@
+/*
+package rules reads and matches window rules in Wingo. Window rules are
+declarative: each rule is a set of match conditions on a new client (its
+class, instance, title, role, window type and whether it is transient) along
+with a set of properties to give the client when it's managed (its workspace,
+head, floating state, geometry, frame, stickiness, opacity and task bar
+visibility).
+
+Unlike hooks, rules are applied while a client is being managed, before it is
+placed or shown. All matching rules are applied in the order in which they are
+defined, so later rules override earlier ones.
+
+This package only knows how to read and match rules. Applying properties to
+a client is done in the xclient package.
+
+Please see config/rules.wini in the Wingo project directory for an explanation
+of how rules can be specified:
+https://github.com/xuanmingyi/wingo/blob/master/config/rules.wini
+*/
+package rules
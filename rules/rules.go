@@ -0,0 +1,327 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/wini"
+)
+
+// All rules read from the configuration file, in the order they're defined.
+var rules = make([]rule, 0)
+
+// Window contains the information about a client that rules can match.
+type Window struct {
+	Class     string
+	Instance  string
+	Title     string
+	Role      string
+	Types     []string // i.e., "_NET_WM_WINDOW_TYPE_NORMAL"
+	Transient bool
+}
+
+// Properties is the result of applying every rule that matches a window.
+// A nil pointer or an empty string means that no rule set that property.
+type Properties struct {
+	Workspace string
+	Head      *int
+
+	Floating    *bool
+	Sticky      *bool
+	SkipTaskbar *bool
+	SkipPager   *bool
+
+	// Geom is relative to the head that the client ends up on. A width or
+	// height of 0 means the client keeps its own width or height.
+	Geom *[4]int
+
+	// One of "full", "borders", "slim" or "nada".
+	Frame string
+
+	// Between 0 (transparent) and 1 (opaque).
+	Opacity *float64
+}
+
+type rule struct {
+	// From the config file. Nice for error messages.
+	name string
+
+	// Each list of match conditions is combined disjunctively, and all lists
+	// are combined conjunctively. An empty list always matches.
+	classes   []string
+	instances []string
+	roles     []string
+	types     []string
+	titles    []*regexp.Regexp
+	transient *bool
+
+	props Properties
+}
+
+// Initialize reads all rules from a wini formatted configuration file. Any
+// rule that can't be read is skipped, with a warning. A missing rules file is
+// not an error, since rules are not essential for Wingo to run. An empty
+// fpath means that there is no rules file at all.
+func Initialize(fpath string) {
+	rules = make([]rule, 0)
+	if err := Reload(fpath); err != nil {
+		logger.Warning.Printf("Could not parse '%s': %s", fpath, err)
+	}
+}
+
+// Reload replaces every rule with those read from a wini formatted
+// configuration file. If the file can't be parsed, an error is returned and
+// the current rules are kept. Rules that can't be read are skipped with a
+// warning. An empty fpath means that there is no rules file, which results in
+// no rules.
+func Reload(fpath string) error {
+	if len(fpath) == 0 {
+		rules = make([]rule, 0)
+		return nil
+	}
+	cdata, err := wini.Parse(fpath)
+	if err != nil {
+		return err
+	}
+	rs := make([]rule, 0)
+	for _, name := range cdata.Sections() {
+		r, err := readSection(cdata, name)
+		if err != nil {
+			logger.Warning.Printf("Could not load rule '%s': %s", name, err)
+			continue
+		}
+		rs = append(rs, r)
+	}
+	rules = rs
+	return nil
+}
+
+// Match returns the properties of every rule that matches w. Properties set
+// by later rules override those set by earlier rules. The second return value
+// is false when no rule matched.
+func Match(w Window) (Properties, bool) {
+	var props Properties
+	matched := false
+	for _, r := range rules {
+		if !r.matches(w) {
+			continue
+		}
+		logger.Lots.Printf("Rule '%s' matched window '%s'.", r.name, w.Title)
+		props.merge(r.props)
+		matched = true
+	}
+	return props, matched
+}
+
+func (r rule) matches(w Window) bool {
+	if !matchSubstring(r.classes, w.Class) {
+		return false
+	}
+	if !matchSubstring(r.instances, w.Instance) {
+		return false
+	}
+	if !matchSubstring(r.roles, w.Role) {
+		return false
+	}
+	if r.transient != nil && *r.transient != w.Transient {
+		return false
+	}
+	if len(r.types) > 0 {
+		found := false
+		for _, typ := range w.Types {
+			if matchType(r.types, typ) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.titles) > 0 {
+		found := false
+		for _, re := range r.titles {
+			if re.MatchString(w.Title) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSubstring returns true if any of the needles is a substring of
+// haystack, ignoring case. (Just like the MatchClient* commands.)
+func matchSubstring(needles []string, haystack string) bool {
+	if len(needles) == 0 {
+		return true
+	}
+	haystack = strings.ToLower(haystack)
+	for _, needle := range needles {
+		if strings.Contains(haystack, strings.ToLower(needle)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchType returns true if typ is one of the window types. Window types
+// may be given in full ("_NET_WM_WINDOW_TYPE_DIALOG") or just by their
+// last part ("dialog").
+func matchType(types []string, typ string) bool {
+	short := strings.TrimPrefix(typ, "_NET_WM_WINDOW_TYPE_")
+	for _, t := range types {
+		if strings.EqualFold(t, typ) || strings.EqualFold(t, short) {
+			return true
+		}
+	}
+	return false
+}
+
+func (props *Properties) merge(other Properties) {
+	if len(other.Workspace) > 0 {
+		props.Workspace = other.Workspace
+	}
+	if other.Head != nil {
+		props.Head = other.Head
+	}
+	if other.Floating != nil {
+		props.Floating = other.Floating
+	}
+	if other.Sticky != nil {
+		props.Sticky = other.Sticky
+	}
+	if other.SkipTaskbar != nil {
+		props.SkipTaskbar = other.SkipTaskbar
+	}
+	if other.SkipPager != nil {
+		props.SkipPager = other.SkipPager
+	}
+	if other.Geom != nil {
+		props.Geom = other.Geom
+	}
+	if len(other.Frame) > 0 {
+		props.Frame = other.Frame
+	}
+	if other.Opacity != nil {
+		props.Opacity = other.Opacity
+	}
+}
+
+// readSection reads a single rule from the configuration file.
+func readSection(cdata *wini.Data, section string) (rule, error) {
+	r := rule{name: section}
+	matchers, props := 0, 0
+	for _, key := range cdata.Keys(section) {
+		vals := key.Strings()
+		last := vals[len(vals)-1]
+		switch key.Name() {
+		case "class":
+			r.classes = vals
+			matchers++
+		case "instance":
+			r.instances = vals
+			matchers++
+		case "role":
+			r.roles = vals
+			matchers++
+		case "type":
+			r.types = vals
+			matchers++
+		case "title":
+			for _, val := range vals {
+				re, err := regexp.Compile(val)
+				if err != nil {
+					return rule{}, key.Err("Invalid regular expression "+
+						"'%s': %s", val, err)
+				}
+				r.titles = append(r.titles, re)
+			}
+			matchers++
+		case "transient":
+			b, err := lastBool(key)
+			if err != nil {
+				return rule{}, err
+			}
+			r.transient = b
+			matchers++
+		case "workspace":
+			r.props.Workspace = last
+			props++
+		case "head":
+			ints, err := key.Ints()
+			if err != nil {
+				return rule{}, err
+			}
+			r.props.Head = &ints[len(ints)-1]
+			props++
+		case "floating", "sticky", "skip_taskbar", "skip_pager":
+			b, err := lastBool(key)
+			if err != nil {
+				return rule{}, err
+			}
+			switch key.Name() {
+			case "floating":
+				r.props.Floating = b
+			case "sticky":
+				r.props.Sticky = b
+			case "skip_taskbar":
+				r.props.SkipTaskbar = b
+			case "skip_pager":
+				r.props.SkipPager = b
+			}
+			props++
+		case "geometry":
+			var geom [4]int
+			n, err := fmt.Sscanf(last, "%d %d %d %d",
+				&geom[0], &geom[1], &geom[2], &geom[3])
+			if err != nil || n != 4 {
+				return rule{}, key.Err("Expected 'x y width height' but "+
+					"got '%s'.", last)
+			}
+			r.props.Geom = &geom
+			props++
+		case "frame":
+			switch f := strings.ToLower(last); f {
+			case "full", "borders", "slim", "nada":
+				r.props.Frame = f
+			default:
+				return rule{}, key.Err("Unknown frame '%s'.", last)
+			}
+			props++
+		case "opacity":
+			floats, err := key.Floats()
+			if err != nil {
+				return rule{}, err
+			}
+			opacity := floats[len(floats)-1]
+			if opacity < 0 || opacity > 1 {
+				return rule{}, key.Err("Opacity must be between 0 and 1.")
+			}
+			r.props.Opacity = &opacity
+			props++
+		default:
+			return rule{}, fmt.Errorf("Unrecognized option '%s'.", key.Name())
+		}
+	}
+	if matchers == 0 {
+		return rule{}, fmt.Errorf("No match conditions were found.")
+	}
+	if props == 0 {
+		return rule{}, fmt.Errorf("No properties were found.")
+	}
+	return r, nil
+}
+
+func lastBool(key wini.Key) (*bool, error) {
+	bools, err := key.Bools()
+	if err != nil {
+		return nil, err
+	}
+	return &bools[len(bools)-1], nil
+}
@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+const testRules = `
+[Firefox]
+class := firefox
+workspace := browser
+frame := slim
+
+[FirefoxDialogs]
+class := firefox
+type := dialog
+transient := yes
+floating := yes
+frame := borders
+
+[Titled]
+title := ^Scratch.*$
+sticky := yes
+opacity := 0.5
+`
+
+func TestMatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wingo-rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fpath := path.Join(dir, "rules.wini")
+	if err := ioutil.WriteFile(fpath, []byte(testRules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	Initialize(fpath)
+	if len(rules) != 3 {
+		t.Fatalf("Expected 3 rules but got %d.", len(rules))
+	}
+
+	props, ok := Match(Window{Class: "Firefox", Title: "Mozilla Firefox",
+		Types: []string{"_NET_WM_WINDOW_TYPE_NORMAL"}})
+	if !ok {
+		t.Fatalf("Expected a normal Firefox window to match.")
+	}
+	if props.Workspace != "browser" || props.Frame != "slim" {
+		t.Fatalf("Unexpected properties: %#v", props)
+	}
+	if props.Floating != nil {
+		t.Fatalf("Normal Firefox windows should not float.")
+	}
+
+	props, ok = Match(Window{Class: "Firefox", Transient: true,
+		Types: []string{"_NET_WM_WINDOW_TYPE_DIALOG"}})
+	if !ok {
+		t.Fatalf("Expected a Firefox dialog to match.")
+	}
+	if props.Workspace != "browser" || props.Frame != "borders" {
+		t.Fatalf("Later rules should override earlier rules: %#v", props)
+	}
+	if props.Floating == nil || !*props.Floating {
+		t.Fatalf("Firefox dialogs should float.")
+	}
+
+	props, ok = Match(Window{Class: "xterm", Title: "Scratchpad"})
+	if !ok || props.Sticky == nil || !*props.Sticky {
+		t.Fatalf("Expected title to match: %#v", props)
+	}
+	if _, ok := Match(Window{Class: "xterm", Title: "A Scratchpad"}); ok {
+		t.Fatalf("Title regex should not have matched.")
+	}
+}
+
+func TestReloadMissing(t *testing.T) {
+	rules = []rule{{name: "Stale"}}
+	if err := Reload(""); err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("Expected no rules without a rules file but got %d.",
+			len(rules))
+	}
+}
@@ -0,0 +1,18 @@
+/*
+package session saves and restores the placement of managed clients across
+Wingo restarts and logouts.
+
+A session is a list of entries, one for each client that was managed when the
+session was saved. Each entry is keyed by the client's WM_CLASS class and
+instance, its WM_WINDOW_ROLE and the command that started it. When a client
+that matches an entry is managed again, the entry is consumed and the
+client's workspace, layout membership, frame and geometry are restored.
+
+A session also records the state of every workspace: the layout in use and
+the state of each of its tiling layouts (see layout.State). Unlike client
+entries, workspace entries are not consumed when they are applied.
+
+This package only knows how to store, load and match entries. Capturing and
+applying them is done in the xclient package.
+*/
+package session
@@ -0,0 +1,212 @@
+package session
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/xuanmingyi/wingo/layout"
+)
+
+// Key identifies a client across restarts. Class and Instance come from
+// WM_CLASS, Role from WM_WINDOW_ROLE and Command from WM_COMMAND (or the
+// command line of the client's process).
+type Key struct {
+	Class    string
+	Instance string
+	Role     string
+	Command  string
+}
+
+// Entry is the saved state of a single client.
+type Entry struct {
+	Key
+
+	// The name of the workspace the client was on. Empty when the client
+	// was sticky or hidden in the scratchpad.
+	Workspace string
+
+	// Floating is true when the client was forced into the floating layer.
+	// (i.e., it did not participate in tiling layouts.)
+	Floating  bool
+	Sticky    bool
+	Maximized bool
+	Iconified bool
+
+	// Scratchpad is true for a client that is shown and hidden with
+	// ScratchpadToggle.
+	Scratchpad bool
+
+	// Frame is one of "full", "borders", "slim" or "nada".
+	Frame string
+
+	// The floating geometry of the client's frame, and the geometry of the
+	// head it was on when it was saved. The head geometry is used to convert
+	// the client geometry if the monitor configuration has changed.
+	X, Y, Width, Height                 int
+	HeadX, HeadY, HeadWidth, HeadHeight int
+}
+
+// HasGeom returns true if the entry carries a valid floating geometry.
+func (e *Entry) HasGeom() bool {
+	return e.Width > 0 && e.Height > 0
+}
+
+// InScratchpad returns true if the client was hidden in the scratchpad.
+func (e *Entry) InScratchpad() bool {
+	return e.Scratchpad && !e.Sticky && len(e.Workspace) == 0
+}
+
+// HasHeadGeom returns true if the entry carries a valid head geometry.
+func (e *Entry) HasHeadGeom() bool {
+	return e.HeadWidth > 0 && e.HeadHeight > 0
+}
+
+// WorkspaceEntry is the saved state of a single workspace.
+type WorkspaceEntry struct {
+	Name string
+
+	// The name of the layout in use.
+	Layout string
+
+	// The state of every tiling layout, keyed by the name of the layout.
+	Layouts map[string]layout.State
+}
+
+// Session is a set of entries waiting to be matched against clients, along
+// with the state of every workspace.
+// It is safe to use from multiple goroutines.
+type Session struct {
+	lock       sync.Mutex
+	Entries    []*Entry
+	Workspaces []*WorkspaceEntry
+}
+
+func New() *Session {
+	return &Session{
+		Entries:    make([]*Entry, 0),
+		Workspaces: make([]*WorkspaceEntry, 0),
+	}
+}
+
+// Load reads a session from the file at fpath. If the file does not exist,
+// an empty session is returned along with the error.
+//
+// Sessions saved before workspaces were included (where the file is just a
+// list of client entries) can still be read.
+func Load(fpath string) (*Session, error) {
+	s := New()
+	bs, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(bs, s); err != nil {
+		if err := json.Unmarshal(bs, &s.Entries); err != nil {
+			return New(), err
+		}
+	}
+	return s, nil
+}
+
+// Save writes the session to the file at fpath, replacing its contents.
+// The file is written to a temporary file first and then renamed, so that a
+// crash in the middle of a save cannot corrupt an existing session.
+func (s *Session) Save(fpath string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	bs, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := fpath + ".tmp"
+	if err := ioutil.WriteFile(tmp, bs, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fpath)
+}
+
+// Add appends an entry to the session.
+func (s *Session) Add(e *Entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Entries = append(s.Entries, e)
+}
+
+// AddWorkspace appends a workspace entry to the session.
+func (s *Session) AddWorkspace(e *WorkspaceEntry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Workspaces = append(s.Workspaces, e)
+}
+
+// WorkspaceEntries returns every workspace entry in the session. Unlike
+// client entries, workspace entries are never consumed.
+func (s *Session) WorkspaceEntries() []*WorkspaceEntry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]*WorkspaceEntry(nil), s.Workspaces...)
+}
+
+// Len returns the number of entries that haven't been matched yet.
+func (s *Session) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(s.Entries)
+}
+
+// Take finds the entry that best matches key, removes it from the session and
+// returns it. If no entry matches, nil is returned.
+//
+// The class and instance must always match exactly. The role and command only
+// need to match when both the key and the entry have them set, but an entry
+// that matches on more of them is preferred. Ties are broken by the order in
+// which entries were saved, so that several windows of the same application
+// are restored in order.
+func (s *Session) Take(key Key) *Entry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	best, bestScore := -1, -1
+	for i, e := range s.Entries {
+		score := e.score(key)
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best == -1 {
+		return nil
+	}
+
+	e := s.Entries[best]
+	s.Entries = append(s.Entries[:best], s.Entries[best+1:]...)
+	return e
+}
+
+// score returns how well the entry matches key. A negative score means that
+// the entry does not match at all.
+func (e *Entry) score(key Key) int {
+	if e.Class != key.Class || e.Instance != key.Instance {
+		return -1
+	}
+
+	score := 0
+	for _, pair := range [][2]string{
+		{e.Role, key.Role},
+		{e.Command, key.Command},
+	} {
+		if len(pair[0]) == 0 || len(pair[1]) == 0 {
+			continue
+		}
+		if pair[0] != pair[1] {
+			return -1
+		}
+		score++
+	}
+	return score
+}
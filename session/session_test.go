@@ -0,0 +1,148 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/BurntSushi/xgb/xproto"
+
+	"github.com/xuanmingyi/wingo/layout"
+)
+
+func TestSessionTake(t *testing.T) {
+	s := New()
+	s.Add(&Entry{Key: Key{Class: "XTerm", Instance: "xterm"},
+		Workspace: "1"})
+	s.Add(&Entry{Key: Key{Class: "XTerm", Instance: "xterm", Role: "mutt"},
+		Workspace: "mail"})
+
+	e := s.Take(Key{Class: "XTerm", Instance: "xterm", Role: "mutt"})
+	if e == nil || e.Workspace != "mail" {
+		t.Fatalf("expected the 'mail' entry, got %+v", e)
+	}
+	if e := s.Take(Key{Class: "URxvt", Instance: "urxvt"}); e != nil {
+		t.Fatalf("expected no match for a different class, got %+v", e)
+	}
+
+	// An entry without a role matches a client with any role.
+	e = s.Take(Key{Class: "XTerm", Instance: "xterm", Role: "irc"})
+	if e == nil || e.Workspace != "1" {
+		t.Fatalf("expected the '1' entry, got %+v", e)
+	}
+	if s.Len() != 0 {
+		t.Fatalf("expected an empty session, got %d entries", s.Len())
+	}
+}
+
+func TestSessionSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wingo-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fpath := path.Join(dir, "session.json")
+	s := New()
+	s.Add(&Entry{
+		Key:      Key{Class: "Firefox", Instance: "Navigator"},
+		Floating: true,
+		Frame:    "slim",
+		X:        10, Y: 20, Width: 800, Height: 600,
+	})
+	s.Add(&Entry{
+		Key:        Key{Class: "URxvt", Instance: "scratch"},
+		Scratchpad: true,
+	})
+	if err := s.Save(fpath); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := loaded.Take(Key{Class: "Firefox", Instance: "Navigator"})
+	if e == nil {
+		t.Fatal("saved entry was not loaded")
+	}
+	if !e.Floating || e.Frame != "slim" || !e.HasGeom() || e.HasHeadGeom() {
+		t.Fatalf("entry did not round trip: %+v", e)
+	}
+
+	e = loaded.Take(Key{Class: "URxvt", Instance: "scratch"})
+	if e == nil {
+		t.Fatal("saved scratchpad entry was not loaded")
+	}
+	if !e.InScratchpad() {
+		t.Fatalf("scratchpad entry did not round trip: %+v", e)
+	}
+}
+
+func TestSessionWorkspaces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wingo-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fpath := path.Join(dir, "session.json")
+	s := New()
+	s.AddWorkspace(&WorkspaceEntry{
+		Name:   "mail",
+		Layout: "Vertical",
+		Layouts: map[string]layout.State{
+			"Vertical": {
+				Masters:          2,
+				MasterProportion: 0.6,
+				Proportions:      []float64{0.5, 0.5},
+				Clients:          []xproto.Window{1, 2},
+			},
+		},
+	})
+	if err := s.Save(fpath); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrks := loaded.WorkspaceEntries()
+	if len(wrks) != 1 || wrks[0].Name != "mail" {
+		t.Fatalf("workspace entry was not loaded: %+v", wrks)
+	}
+	st := wrks[0].Layouts["Vertical"]
+	if st.Masters != 2 || st.MasterProportion != 0.6 || len(st.Clients) != 2 {
+		t.Fatalf("layout state did not round trip: %+v", st)
+	}
+
+	// A layout state without the number of masters doesn't change it.
+	partial := `{"Workspaces": [{"Name": "mail", "Layout": "Vertical",
+		"Layouts": {"Vertical": {"Clients": [2, 1]}}}]}`
+	if err := ioutil.WriteFile(fpath, []byte(partial), 0666); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = Load(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st = loaded.WorkspaceEntries()[0].Layouts["Vertical"]
+	if st.Masters >= 0 || len(st.Clients) != 2 || st.Clients[0] != 2 {
+		t.Fatalf("partial layout state was not loaded correctly: %+v", st)
+	}
+
+	// Sessions saved as a plain list of clients can still be loaded.
+	old := `[{"Class": "XTerm", "Instance": "xterm", "Workspace": "1"}]`
+	if err := ioutil.WriteFile(fpath, []byte(old), 0666); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = Load(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := loaded.Take(Key{Class: "XTerm", Instance: "xterm"}); e == nil {
+		t.Fatal("entry from an old session was not loaded")
+	}
+}
@@ -2,6 +2,7 @@ package text
 
 import (
 	"image"
+	"unicode"
 
 	"github.com/BurntSushi/freetype-go/freetype/truetype"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/keybind"
+	"github.com/BurntSushi/xgbutil/xevent"
 	"github.com/BurntSushi/xgbutil/xgraphics"
 	"github.com/BurntSushi/xgbutil/xwindow"
 
@@ -20,6 +22,10 @@ import (
 // an input window. The only exposed information is the Text field, in case
 // you need to inspect it. Input values should *only* be made with the
 // NewInput constructor.
+//
+// Text is edited at the cursor, which can be moved with the keys handled by
+// the Edit method or by clicking in the input box. Text can also be selected,
+// in which case typing replaces the selected text.
 type Input struct {
 	Text []rune
 	*xwindow.Window
@@ -30,6 +36,19 @@ type Input struct {
 	fontColor render.Color
 	bgColor   render.Color
 	padding   int
+
+	// cursor is the position of the cursor in Text, and anchor is the other
+	// end of the selection. Nothing is selected when they are equal.
+	cursor, anchor int
+
+	// xs is the position of each character of Text when it was last drawn,
+	// and scroll is how far (in pixels) the text is scrolled to the left so
+	// that the cursor is visible.
+	xs     []int
+	scroll int
+
+	// pasted is called after text is pasted. See OnPaste.
+	pasted func()
 }
 
 // NewInput constructs Input values. It needs an X connection, a parent window,
@@ -53,7 +72,7 @@ func NewInput(X *xgbutil.XUtil, parent xproto.Window, width int, padding int,
 
 	img := xgraphics.New(X, image.Rect(0, 0, width, height))
 	win := xwindow.Must(xwindow.Create(X, parent))
-	win.Listen(xproto.EventMaskKeyPress)
+	win.Listen(xproto.EventMaskKeyPress | xproto.EventMaskButtonPress)
 	win.Resize(width, height)
 
 	ti := &Input{
@@ -67,24 +86,75 @@ func NewInput(X *xgbutil.XUtil, parent xproto.Window, width int, padding int,
 		padding:   padding,
 	}
 
+	xevent.ButtonPressFun(ti.buttonPress).Connect(X, win.Id)
+	xevent.SelectionNotifyFun(ti.selectionNotify).Connect(X, win.Id)
+
 	ti.Render()
 	return ti
 }
 
 // Render will redraw the background image, and write whatever text is in
-// (*Input).Text to the image. No clean-up by the caller is necessary.
+// (*Input).Text to the image, along with the cursor and the selected text.
+// No clean-up by the caller is necessary.
 //
 // Render probably should not be called, unless you are manipulating
 // (*Input).Text manually. Otherwise, it is preferrable to use the Add, Remove
 // and Reset methods.
 func (ti *Input) Render() {
+	ti.cursor = clamp(ti.cursor, 0, len(ti.Text))
+	ti.anchor = clamp(ti.anchor, 0, len(ti.Text))
+
 	r, g, b := ti.bgColor.RGB8()
 	ti.img.ForExp(func(x, y int) (uint8, uint8, uint8, uint8) {
 		return r, g, b, 0xff
 	})
 
-	ti.img.Text(ti.padding, ti.padding,
-		ti.fontColor.ImageColor(), ti.fontSize, ti.font, string(ti.Text))
+	txt := []rune(printable(string(ti.Text)))
+	ti.xs, _, _ = layout(ti.font, ti.fontSize, txt)
+
+	// Scroll the text so that the cursor is visible, without leaving empty
+	// space at the end of the input box if the text is long enough.
+	bounds := ti.img.Bounds()
+	width := bounds.Dx() - 2*ti.padding
+	cursorx, endx := ti.xs[ti.cursor], ti.xs[len(txt)]
+	if cursorx-ti.scroll >= width {
+		ti.scroll = cursorx - width + 1
+	}
+	if cursorx-ti.scroll < 0 {
+		ti.scroll = cursorx
+	}
+	if endx-ti.scroll < width-1 {
+		ti.scroll = clamp(endx-width+1, 0, ti.scroll)
+	}
+
+	// The text is drawn inside the padding, so that scrolled text doesn't
+	// spill into it.
+	top, bot := ti.padding, bounds.Dy()-ti.padding
+	clip := ti.img.SubImage(image.Rect(ti.padding, 0, ti.padding+width,
+		bounds.Dy())).(*xgraphics.Image)
+	x := ti.padding - ti.scroll
+	clip.Text(x, top, ti.fontColor.ImageColor(), ti.fontSize, ti.font,
+		string(txt))
+
+	// The selected text is drawn with the colors swapped.
+	if start, end := ti.selection(); start < end {
+		sx, ex := x+ti.xs[start], x+ti.xs[end]
+		if sel := clip.SubImage(image.Rect(sx, top, ex, bot)); sel != nil {
+			sel := sel.(*xgraphics.Image)
+			fr, fg, fb := ti.fontColor.RGB8()
+			sel.ForExp(func(x, y int) (uint8, uint8, uint8, uint8) {
+				return fr, fg, fb, 0xff
+			})
+			sel.Text(sx, top, ti.bgColor.ImageColor(), ti.fontSize, ti.font,
+				string(txt[start:end]))
+		}
+	}
+
+	// The cursor is a vertical line.
+	clr := ti.fontColor.ImageColor()
+	for y := top; y < bot; y++ {
+		clip.Set(x+cursorx, y, clr)
+	}
 
 	ti.img.XSurfaceSet(ti.Id)
 	ti.img.XDraw()
@@ -93,62 +163,249 @@ func (ti *Input) Render() {
 }
 
 // Add will convert a (modifiers, keycode) tuple taken directly from a
-// Key{Press,Release}Event to a single character string.
+// Key{Press,Release}Event to a single character and insert it at the cursor.
 // Note that sometimes this conversion will fail. When it fails, a message will
 // be logged and no text will be added.
 //
 // Note that sometimes the conversion should fail (like when the Shift key
-// is pressed), and other times it will fail because the xgbutil/keybind
-// package provides inadequate support for keyboard encodings.
-//
-// I suspect that languages other than English will completely fail here.
+// is pressed, or when the Control key is held down), and other times it will
+// fail because the key's keysym isn't a Latin-1 or Unicode keysym. (See
+// LookupRune.)
 //
 // If a work-around is desperately needed, use AddLetter.
 func (ti *Input) Add(mods uint16, kc xproto.Keycode) {
-	s := keybind.LookupString(ti.X, mods, kc)
-	if len(s) != 1 {
-		logger.Lots.Printf("(*Input).Add: Could not translate string '%s' "+
-			"received from the keyboard to a single character.", s)
+	char := LookupRune(ti.X, mods, kc)
+	if char == 0 {
+		logger.Lots.Printf("(*Input).Add: Could not translate keycode %d "+
+			"(with modifiers %d) received from the keyboard to a "+
+			"character.", kc, mods)
 		return
 	}
-	ti.AddLetter(rune(s[0]))
+	ti.AddLetter(char)
 }
 
-// AddLetter will add a single character to the input and re-render the input
-// box. Note that the Add method is quite convenient and should be used when
-// reading Key{Press,Release} events. If input is coming from somewhere else,
-// or if Add is not working, resort to AddLetter.
+// AddLetter will insert a single character at the cursor (replacing the
+// selected text) and re-render the input box. Note that the Add method is
+// quite convenient and should be used when reading Key{Press,Release} events.
+// If input is coming from somewhere else, or if Add is not working, resort to
+// AddLetter.
 func (ti *Input) AddLetter(char rune) {
 	if char == 0 {
-		logger.Warning.Printf("(*Input).Add: Strange input: '%s'.", char)
+		logger.Warning.Printf("(*Input).Add: Strange input: %q.", char)
 		return
 	}
 
-	ti.Text = append(ti.Text, char)
+	ti.insert([]rune{char})
 	ti.Render()
 }
 
-// SetString will clear the input box and set it to the string provided.
+// SetString will clear the input box and set it to the string provided. The
+// cursor is put at the end.
 func (ti *Input) SetString(s string) {
-	ti.Text = make([]rune, 0, 50)
-	for _, char := range s {
-		ti.Text = append(ti.Text, char)
-	}
+	ti.Text = []rune(s)
+	ti.cursor, ti.anchor = len(ti.Text), len(ti.Text)
 	ti.Render()
 }
 
-// Remove will remove the last character in the input box and re-render.
-// If there are no characters in the input, Remove has no effect.
+// Remove will remove the character before the cursor and re-render. If text
+// is selected, the selected text is removed instead.
+// If there are no characters before the cursor, Remove has no effect.
 func (ti *Input) Remove() {
-	if len(ti.Text) == 0 {
-		return
+	if start, end := ti.selection(); start < end {
+		ti.delete(start, end)
+	} else if ti.cursor > 0 {
+		ti.delete(ti.cursor-1, ti.cursor)
 	}
-	ti.Text = ti.Text[:len(ti.Text)-1]
 	ti.Render()
 }
 
 // Reset will clear the entire input box and re-render.
 func (ti *Input) Reset() {
 	ti.Text = make([]rune, 0, 50)
+	ti.cursor, ti.anchor, ti.scroll = 0, 0, 0
+	ti.Render()
+}
+
+// Edit handles the keys that move the cursor and edit the text around it,
+// and re-renders the input box. It returns false if the key press isn't one
+// of them, in which case it should probably be given to Add. The keys are:
+//
+//	Left, Right          Move the cursor by one character.
+//	Control-Left/Right   Move the cursor by one word.
+//	Home, Control-a      Move the cursor to the start.
+//	End, Control-e       Move the cursor to the end.
+//	Delete               Remove the character after the cursor.
+//	Control-Delete       Remove the word after the cursor.
+//	Control-BackSpace/w  Remove the word before the cursor.
+//	Control-k            Remove everything after the cursor.
+//	Control-u            Remove everything before the cursor.
+//	Control-v            Paste the clipboard.
+//	Shift-Insert         Paste the primary selection.
+//
+// Holding Shift while moving the cursor selects text. (Removing text removes
+// the selected text instead, if any.)
+//
+// Note that the BackSpace key isn't handled, since what removes a character
+// is up to the caller. (See Remove.)
+func (ti *Input) Edit(mods uint16, kc xproto.Keycode) bool {
+	ctrl := mods&xproto.ModMaskControl > 0
+	shift := mods&xproto.ModMaskShift > 0
+	key := func(keyStr string) bool {
+		return keybind.KeyMatch(ti.X, keyStr, mods, kc)
+	}
+
+	switch {
+	case key("Left") && ctrl:
+		ti.move(ti.wordLeft(), shift)
+	case key("Left"):
+		ti.move(ti.cursor-1, shift)
+	case key("Right") && ctrl:
+		ti.move(ti.wordRight(), shift)
+	case key("Right"):
+		ti.move(ti.cursor+1, shift)
+	case key("Home"), key("a") && ctrl:
+		ti.move(0, shift)
+	case key("End"), key("e") && ctrl:
+		ti.move(len(ti.Text), shift)
+	case key("Delete"):
+		if start, end := ti.selection(); start < end {
+			ti.delete(start, end)
+		} else if ctrl {
+			ti.delete(ti.cursor, ti.wordRight())
+		} else {
+			ti.delete(ti.cursor, ti.cursor+1)
+		}
+	case ctrl && (key("BackSpace") || key("w")):
+		if start, end := ti.selection(); start < end {
+			ti.delete(start, end)
+		} else {
+			ti.delete(ti.wordLeft(), ti.cursor)
+		}
+	case ctrl && key("k"):
+		ti.delete(ti.cursor, len(ti.Text))
+	case ctrl && key("u"):
+		ti.delete(0, ti.cursor)
+	case ctrl && key("v"):
+		ti.Paste("CLIPBOARD")
+	case shift && key("Insert"):
+		ti.Paste("PRIMARY")
+	default:
+		return false
+	}
+	ti.Render()
+	return true
+}
+
+// OnPaste sets a function to be called whenever text is pasted into the
+// input box. Since pasted text arrives after Paste (or Edit) returns, this is
+// the only way to know when Text was changed by pasting.
+func (ti *Input) OnPaste(f func()) {
+	ti.pasted = f
+}
+
+// buttonPress moves the cursor to where the input box was clicked. The middle
+// button also pastes the primary selection there.
+func (ti *Input) buttonPress(X *xgbutil.XUtil, ev xevent.ButtonPressEvent) {
+	if ev.Detail != 1 && ev.Detail != 2 {
+		return
+	}
+
+	// Find the closest position between two characters.
+	x := int(ev.EventX) - ti.padding + ti.scroll
+	pos := 0
+	for i := range ti.xs {
+		if abs(ti.xs[i]-x) < abs(ti.xs[pos]-x) {
+			pos = i
+		}
+	}
+	ti.move(pos, false)
 	ti.Render()
+
+	if ev.Detail == 2 {
+		ti.Paste("PRIMARY")
+	}
+}
+
+// move moves the cursor to pos. The selection is extended if selecting is
+// true, otherwise it is cleared.
+func (ti *Input) move(pos int, selecting bool) {
+	ti.cursor = clamp(pos, 0, len(ti.Text))
+	if !selecting {
+		ti.anchor = ti.cursor
+	}
+}
+
+// selection returns the start and end of the selected text. They're the
+// same if nothing is selected.
+func (ti *Input) selection() (int, int) {
+	if ti.anchor < ti.cursor {
+		return ti.anchor, ti.cursor
+	}
+	return ti.cursor, ti.anchor
+}
+
+// insert replaces the selected text with s, and puts the cursor after it.
+func (ti *Input) insert(s []rune) {
+	start, end := ti.selection()
+	text := make([]rune, 0, len(ti.Text)-(end-start)+len(s))
+	text = append(text, ti.Text[:start]...)
+	text = append(text, s...)
+	text = append(text, ti.Text[end:]...)
+
+	ti.Text = text
+	ti.cursor, ti.anchor = start+len(s), start+len(s)
+}
+
+// delete removes the characters from start up to end, and puts the cursor
+// where they were.
+func (ti *Input) delete(start, end int) {
+	start = clamp(start, 0, len(ti.Text))
+	end = clamp(end, start, len(ti.Text))
+	ti.Text = append(ti.Text[:start], ti.Text[end:]...)
+	ti.cursor, ti.anchor = start, start
+}
+
+// wordLeft returns the position of the start of the word before the cursor.
+func (ti *Input) wordLeft() int {
+	pos := ti.cursor
+	for pos > 0 && !isWord(ti.Text[pos-1]) {
+		pos--
+	}
+	for pos > 0 && isWord(ti.Text[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// wordRight returns the position of the end of the word after the cursor.
+func (ti *Input) wordRight() int {
+	pos := ti.cursor
+	for pos < len(ti.Text) && !isWord(ti.Text[pos]) {
+		pos++
+	}
+	for pos < len(ti.Text) && isWord(ti.Text[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func clamp(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
 }
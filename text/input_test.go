@@ -0,0 +1,82 @@
+package text
+
+import (
+	"testing"
+)
+
+func TestWordJumps(t *testing.T) {
+	tests := []struct {
+		text                string
+		cursor, left, right int
+	}{
+		{"foo bar", 7, 4, 7},
+		{"foo bar", 0, 0, 3},
+		{"foo bar", 3, 0, 7},
+		{"foo.bar-baz", 4, 0, 7},
+		{"foo.bar-baz", 8, 4, 11},
+		{"snake_case name", 0, 0, 10},
+		{"héllo wörld", 11, 6, 11},
+		{"héllo wörld", 0, 0, 5},
+		{"日本語 テキスト", 8, 4, 8},
+		{"日本語 テキスト", 0, 0, 3},
+		{"  ...  ", 0, 0, 7},
+		{"  ...  ", 7, 0, 7},
+		{"", 0, 0, 0},
+	}
+	for _, test := range tests {
+		ti := &Input{Text: []rune(test.text)}
+		ti.move(test.cursor, false)
+		if left := ti.wordLeft(); left != test.left {
+			t.Errorf("Word left of %d in %q: expected %d but got %d.",
+				test.cursor, test.text, test.left, left)
+		}
+		if right := ti.wordRight(); right != test.right {
+			t.Errorf("Word right of %d in %q: expected %d but got %d.",
+				test.cursor, test.text, test.right, right)
+		}
+	}
+}
+
+func TestInsert(t *testing.T) {
+	ti := &Input{Text: []rune("héllo")}
+	ti.move(1, false)
+	ti.insert([]rune("ä"))
+	if string(ti.Text) != "häéllo" || ti.cursor != 2 || ti.anchor != 2 {
+		t.Fatalf("Unexpected insert: %q (cursor %d, anchor %d).",
+			string(ti.Text), ti.cursor, ti.anchor)
+	}
+
+	// Typing replaces the selection, whichever way it was made.
+	ti.move(5, false)
+	ti.move(1, true)
+	ti.insert([]rune("日本"))
+	if string(ti.Text) != "h日本o" || ti.cursor != 3 || ti.anchor != 3 {
+		t.Fatalf("Unexpected insert over selection: %q (cursor %d, "+
+			"anchor %d).", string(ti.Text), ti.cursor, ti.anchor)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		text       string
+		start, end int
+		expected   string
+		cursor     int
+	}{
+		{"foo bar", 3, 7, "foo", 3},
+		{"foo bar", 0, 4, "bar", 0},
+		{"wörld", 1, 2, "wrld", 1},
+		{"foo", 2, 10, "fo", 2},
+		{"foo", -1, 1, "oo", 0},
+		{"foo", 2, 1, "foo", 2},
+	}
+	for _, test := range tests {
+		ti := &Input{Text: []rune(test.text)}
+		ti.delete(test.start, test.end)
+		if string(ti.Text) != test.expected || ti.cursor != test.cursor {
+			t.Errorf("Deleting %d:%d of %q: expected %q (cursor %d) but "+
+				"got %q (cursor %d).", test.start, test.end, test.text,
+				test.expected, test.cursor, string(ti.Text), ti.cursor)
+		}
+	}
+}
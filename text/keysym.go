@@ -0,0 +1,84 @@
+package text
+
+import (
+	"unicode"
+
+	"github.com/BurntSushi/xgb/xproto"
+
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/keybind"
+)
+
+// LookupRune returns the character typed by the key press given, or 0 if the
+// key doesn't type a character (like the Shift or Left keys) or if the
+// Control key is pressed.
+//
+// Unlike keybind.LookupString, LookupRune works with keys that type
+// characters outside of ASCII, as long as their keysyms are Latin-1 or
+// Unicode keysyms. (Which is what most keyboard layouts use.)
+func LookupRune(X *xgbutil.XUtil, mods uint16, kc xproto.Keycode) rune {
+	if mods&xproto.ModMaskControl > 0 {
+		return 0
+	}
+
+	// On an XKB server, the core keyboard mapping of a key has the keysyms
+	// of the first group (without and with Shift) in columns 0 and 1, those
+	// of the second group in columns 2 and 3, and the third and fourth levels
+	// of the first group in columns 4 and 5. AltGr (ISO_Level3_Shift, Mod5
+	// usually) selects the third level. The second group is selected by the
+	// group bits of the key event's state (which is how XKB reports
+	// Mode_switch and group switches to core clients). Empty columns fall
+	// back to columns 0 and 1.
+	var col byte
+	switch {
+	case mods&xproto.ModMask5 > 0 && hasKeysyms(X, kc, 4):
+		col = 4
+	case mods&keyGroupMask > 0 && hasKeysyms(X, kc, 2):
+		col = 2
+	}
+	lower := keysymRune(keybind.KeysymGet(X, kc, col))
+	upper := keysymRune(keybind.KeysymGet(X, kc, col+1))
+	if upper == 0 {
+		lower, upper = unicode.ToLower(lower), unicode.ToUpper(lower)
+	}
+
+	if mods&xproto.ModMaskShift > 0 {
+		return upper
+	}
+	return lower
+}
+
+// keyGroupMask is the part of a key event's state that holds the keyboard
+// group, on an XKB server.
+const keyGroupMask = 0x6000
+
+// hasKeysyms returns true if the keyboard mapping of the key given has the
+// columns col and col+1, with a keysym in column col.
+func hasKeysyms(X *xgbutil.XUtil, kc xproto.Keycode, col byte) bool {
+	return int(col)+1 < int(keybind.KeyMapGet(X).KeysymsPerKeycode) &&
+		keybind.KeysymGet(X, kc, col) != 0
+}
+
+// keysymRune returns the character corresponding to a keysym, or 0 if there
+// is none.
+func keysymRune(ks xproto.Keysym) rune {
+	switch {
+	case ks >= 0x20 && ks <= 0x7e, ks >= 0xa0 && ks <= 0xff:
+		// Latin-1 keysyms are the same as their code points.
+		return rune(ks)
+	case ks >= 0x1000100 && ks <= 0x110ffff:
+		// Unicode keysyms are code points with 0x1000000 added.
+		return rune(ks - 0x1000000)
+	case ks == 0xff80:
+		// KP_Space
+		return ' '
+	case ks >= 0xffaa && ks <= 0xffbd:
+		// Other keypad keysyms are ASCII with 0xff80 added.
+		if r := rune(ks - 0xff80); r <= '9' || r == '=' {
+			return r
+		}
+	case ks == 0x20ac:
+		return '€'
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/xgb/xproto"
+)
+
+func TestKeysymRune(t *testing.T) {
+	tests := []struct {
+		keysym   xproto.Keysym
+		expected rune
+	}{
+		{0x61, 'a'},               // a
+		{0x41, 'A'},               // A
+		{0x20, ' '},               // space
+		{0xe9, 'é'},               // eacute
+		{0xdf, 'ß'},               // ssharp
+		{0x10020ac, '€'},          // U+20AC
+		{0x1000439, 'й'},          // U+0439
+		{0x101f600, '\U0001F600'}, // U+1F600
+		{0x20ac, '€'},             // EuroSign
+		{0xffb7, '7'},             // KP_7
+		{0xffab, '+'},             // KP_Add
+		{0xffae, '.'},             // KP_Decimal
+		{0xffbd, '='},             // KP_Equal
+		{0xff80, ' '},             // KP_Space
+		{0xff8d, 0},               // KP_Enter
+		{0xff51, 0},               // Left
+		{0xffe1, 0},               // Shift_L
+		{0xff08, 0},               // BackSpace
+		{0x7f, 0},                 // (not a keysym)
+		{0x100001f, 0},            // U+001F (a control character)
+	}
+	for _, test := range tests {
+		if r := keysymRune(test.keysym); r != test.expected {
+			t.Errorf("Keysym 0x%x: expected %q but got %q.",
+				test.keysym, test.expected, r)
+		}
+	}
+}
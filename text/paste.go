@@ -0,0 +1,95 @@
+package text
+
+import (
+	"unicode"
+
+	"github.com/BurntSushi/xgb/xproto"
+
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/xevent"
+	"github.com/BurntSushi/xgbutil/xprop"
+
+	"github.com/xuanmingyi/wingo/logger"
+)
+
+// pasteProperty is the property of the input window that the owner of a
+// selection writes the selection's contents to.
+const pasteProperty = "_WINGO_PASTE"
+
+// Paste inserts the contents of the X selection given (usually "CLIPBOARD"
+// or "PRIMARY") at the cursor, replacing the selected text. The contents are
+// asked for from the selection's owner, and inserted when they arrive. (After
+// Paste has returned. See OnPaste.)
+//
+// Text is pasted as a single line: line breaks and tabs become spaces.
+func (ti *Input) Paste(selection string) {
+	ti.convertSelection(selection, "UTF8_STRING")
+}
+
+// convertSelection asks the owner of selection to convert it to target, and
+// to write it to the paste property.
+func (ti *Input) convertSelection(selection, target string) {
+	atoms := make([]xproto.Atom, 3)
+	for i, name := range []string{selection, target, pasteProperty} {
+		atom, err := xprop.Atm(ti.X, name)
+		if err != nil {
+			logger.Warning.Printf("Could not paste '%s': %s", selection, err)
+			return
+		}
+		atoms[i] = atom
+	}
+	xproto.ConvertSelection(ti.X.Conn(), ti.Id, atoms[0], atoms[1], atoms[2],
+		ti.X.TimeGet())
+}
+
+// selectionNotify inserts the pasted text, once the owner of the selection
+// has written it to the paste property.
+func (ti *Input) selectionNotify(X *xgbutil.XUtil,
+	ev xevent.SelectionNotifyEvent) {
+
+	if ev.Property == xproto.AtomNone {
+		// The owner can't convert the selection to UTF-8. Try again with
+		// Latin-1 text, which every owner should support.
+		target, err := xprop.AtomName(X, ev.Target)
+		if err == nil && target == "UTF8_STRING" {
+			if selection, err := xprop.AtomName(X, ev.Selection); err == nil {
+				ti.convertSelection(selection, "STRING")
+			}
+		}
+		return
+	}
+
+	reply, err := xprop.GetProperty(X, ti.Id, pasteProperty)
+	xproto.DeleteProperty(X.Conn(), ti.Id, ev.Property)
+	if err != nil {
+		logger.Warning.Printf("Could not paste: %s", err)
+		return
+	}
+
+	var pasted []rune
+	switch typ, _ := xprop.AtomName(X, reply.Type); typ {
+	case "INCR":
+		logger.Warning.Printf("Could not paste: The selection is too big.")
+		return
+	case "STRING":
+		for _, b := range reply.Value {
+			pasted = append(pasted, rune(b))
+		}
+	default:
+		pasted = []rune(string(reply.Value))
+	}
+	for len(pasted) > 0 && unicode.IsSpace(pasted[len(pasted)-1]) {
+		pasted = pasted[:len(pasted)-1]
+	}
+	for i, r := range pasted {
+		if unicode.IsControl(r) {
+			pasted[i] = ' '
+		}
+	}
+
+	ti.insert(pasted)
+	ti.Render()
+	if ti.pasted != nil {
+		ti.pasted()
+	}
+}
@@ -2,6 +2,8 @@ package text
 
 import (
 	"image"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/BurntSushi/freetype-go/freetype/truetype"
 
@@ -11,18 +13,6 @@ import (
 	"github.com/xuanmingyi/wingo/render"
 )
 
-// BUG(burntsushi): I don't think freetype-go has a way to compute text extents
-// before rendering text to an image. To work-around this, DrawText will over
-// estimate the extents by assuming each character has a width equal to 1 em,
-// and draw the text on an image with width = len(text) * pixels-per-em and
-// a height = pixels-per-em. freetype-go then returns the point advanced by the
-// drawn text, and this function uses it to take a sub-image of the image which
-// is then drawn to the window. Unfortunately, this point doesn't reflect the
-// true bounding box of the text (it cuts off the part of the text that dips
-// below the base line). So to work-around this, the height of the extents is
-// padded by a fixed pixel amount. This is wrong and will break if the font
-// size is too large.
-
 // DrawText is a convenience function that will create a new image, render
 // the provided text to it, paint the image to the provided window, and resize
 // the window to fit the text snugly.
@@ -35,30 +25,98 @@ func DrawText(win *xwindow.Window, font *truetype.Font, size float64,
 	if len(text) == 0 {
 		text = " "
 	}
+	text = printable(text)
 
-	// Over estimate the extents.
-	ew, eh := xgraphics.Extents(font, size, text)
+	// Measure the text glyph by glyph. If a glyph sticks out to the left of
+	// where the text starts, the text is moved over to make room for it.
+	_, left, right := layout(font, size, []rune(text))
+	_, eh := xgraphics.Extents(font, size, text)
+	ew := right - left
 
-	// Create an image using the over estimated extents.
+	// Create an image using the extents.
 	img := xgraphics.New(win.X, image.Rect(0, 0, ew, eh))
 	xgraphics.BlendBgColor(img, bgClr.ImageColor())
 
-	// Now draw the text, grab the (x, y) position advanced by the text, and
-	// check for an error in rendering.
-	_, _, err := img.Text(0, 0, fontClr.ImageColor(), size, font, text)
+	// Now draw the text and check for an error in rendering.
+	_, _, err := img.Text(-left, 0, fontClr.ImageColor(), size, font, text)
 	if err != nil {
 		return err
 	}
 
-	// Resize the window to the geometry determined by (x, y).
+	// Resize the window to fit the text.
 	win.Resize(ew, eh)
 
 	// Now draw the image to the window and destroy it.
 	img.XSurfaceSet(win.Id)
-	// subimg := img.SubImage(image.Rect(0, 0, ew, eh))
 	img.XDraw()
 	img.XPaint(win.Id)
 	img.Destroy()
 
 	return nil
 }
+
+// Extents returns the width and height of text when drawn with DrawText.
+//
+// Unlike xgraphics.Extents, the width accounts for glyphs that are wider than
+// the space they advance by (italics, and many CJK glyphs and symbols), so
+// that they aren't cut off.
+func Extents(font *truetype.Font, size float64, text string) (int, int) {
+	text = printable(text)
+	_, left, right := layout(font, size, []rune(text))
+	_, eh := xgraphics.Extents(font, size, text)
+	return right - left, eh
+}
+
+// layout computes where each rune of text is drawn, in pixels, starting at 0.
+// The returned slice has one more element than text: the position after the
+// last rune. left and right are the horizontal bounds of everything drawn,
+// which may go past the first and last positions.
+//
+// The computation mirrors freetype-go's DrawString, including kerning.
+func layout(font *truetype.Font, size float64,
+	text []rune) (xs []int, left, right int) {
+
+	// freetype-go uses 72 DPI, and 26.6 fixed point values for glyph metrics.
+	scale := int32(size * 72 * (64.0 / 72.0))
+	glyph := truetype.NewGlyphBuf()
+
+	var x, min, max int32
+	xs = make([]int, len(text)+1)
+	prev, hasPrev := truetype.Index(0), false
+	for i, r := range text {
+		index := font.Index(r)
+		if hasPrev {
+			x += font.Kerning(scale, prev, index)
+		}
+		xs[i] = int((x + 32) >> 6)
+
+		if err := glyph.Load(font, scale, index, nil); err == nil {
+			if x+glyph.B.XMin < min {
+				min = x + glyph.B.XMin
+			}
+			if x+glyph.B.XMax > max {
+				max = x + glyph.B.XMax
+			}
+		}
+		x += font.HMetric(scale, index).AdvanceWidth
+		prev, hasPrev = index, true
+	}
+	xs[len(text)] = int((x + 32) >> 6)
+	if x > max {
+		max = x
+	}
+	return xs, int(min >> 6), int((max + 63) >> 6)
+}
+
+// printable replaces characters that freetype-go can't look up in a font
+// (those outside the Basic Multilingual Plane, like most emoji) with the
+// Unicode replacement character. Otherwise, some unrelated glyph is drawn in
+// their place. Invalid UTF-8 is also replaced.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0xffff {
+			return utf8.RuneError
+		}
+		return r
+	}, s)
+}
@@ -21,6 +21,12 @@ The flags are:
 		may contain multiple commands, where each is one its own line and
 		enclosed with '(' and ')'.
 		If "-" is used, commands will be read from stdin.
+	--json
+		Send the command as a JSON request and print the JSON response,
+		which distinguishes between errors and return values. If the
+		command starts with '{', it is sent unchanged. i.e.,
+
+			wingo-cmd --json '{"id": 1, "command": "AddWorkspace", "args": ["www"]}'
 	--list
 		List all commands and the names of each parameter for each command.
 	--list-types
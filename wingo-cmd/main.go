@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 
 var (
 	flagFileInput         = ""
+	flagJSON              = false
 	flagListCommands      = false
 	flagListTypeCommands  = false
 	flagListUsageCommands = false
@@ -32,6 +34,10 @@ func init() {
 	flag.StringVar(&flagFileInput, "f", flagFileInput,
 		"When set, commands will be read from the specified file.\n"+
 			"If '-' is used, commands will be read from stdin.")
+	flag.BoolVar(&flagJSON, "json", flagJSON,
+		"When set, commands are sent as a JSON request and the JSON\n"+
+			"response is printed. If the command starts with '{', it is\n"+
+			"sent as is.")
 	flag.BoolVar(&flagListCommands, "list", flagListCommands,
 		"Print a list of all commands and their parameters.")
 	flag.BoolVar(&flagListTypeCommands, "list-types", flagListTypeCommands,
@@ -57,6 +63,9 @@ func main() {
 
 	// Get the commands from file/stdin/argument.
 	cmds := getCommands()
+	if flagJSON {
+		cmds = jsonRequest(cmds)
+	}
 
 	// Connect to the Wingo command server.
 	conn, err := net.Dial("unix", socketFilePath())
@@ -90,6 +99,22 @@ func main() {
 	}
 }
 
+// jsonRequest wraps the given commands in a JSON request. Commands that
+// already look like a JSON request are returned unchanged.
+func jsonRequest(cmds string) string {
+	if strings.HasPrefix(strings.TrimSpace(cmds), "{") {
+		return cmds
+	}
+	bs, err := json.Marshal(map[string]interface{}{
+		"id":      1,
+		"command": cmds,
+	})
+	if err != nil {
+		log.Fatalf("Could not encode JSON request: %s", err)
+	}
+	return string(bs)
+}
+
 func socketFilePath() string {
 	c := cmd.New("wingo", "--show-socket")
 	if err := c.Run(); err != nil {
@@ -28,6 +28,7 @@ import (
 
 type Data struct {
 	data      map[string]Section // section -> option -> values
+	sections  []string           // section names in the order they appear
 	variables map[string]string
 }
 type Section map[string]Value
@@ -117,6 +118,7 @@ func (d *Data) parseLine(section, line string, lnum int) (string, error) {
 
 		// good to go, make the new section
 		d.data[skey] = make(Section)
+		d.sections = append(d.sections, skey)
 		return skey, nil
 	}
 
@@ -178,15 +180,11 @@ func (d *Data) varReplace(val string) string {
 	return findVar.ReplaceAllStringFunc(val, replace)
 }
 
+// Sections returns the names of all sections in the order in which they
+// appear in the file.
 func (d *Data) Sections() []string {
-	sections := make([]string, len(d.data))
-
-	i := 0
-	for s, _ := range d.data {
-		sections[i] = s
-		i++
-	}
-
+	sections := make([]string, len(d.sections))
+	copy(sections, d.sections)
 	return sections
 }
 
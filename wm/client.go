@@ -20,6 +20,7 @@ type Client interface {
 	ImminentDestruction() bool
 	IsMaximized() bool
 	Remaximize()
+	Reload()
 
 	CycleItem() *prompt.CycleItem
 	SelectItem() *prompt.SelectItem
@@ -5,6 +5,7 @@ import (
 
 	"github.com/BurntSushi/xgbutil/ewmh"
 
+	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/misc"
 	"github.com/xuanmingyi/wingo/wini"
@@ -22,10 +23,24 @@ type Configuration struct {
 	FfmHead             bool
 	Workspaces          []string
 	DefaultLayout       string
+	GapsInner           int
+	GapsOuter           int
+	SmartGaps           bool
+	Placement           layout.Placement
+	WorkspacePlacement  map[string]layout.Placement
+	SnapThreshold       int
+	SnapToWindows       bool
+	EdgeResistance      int
+	DragSnap            bool
 	PopupTime           int
 	ShowFyi, ShowErrors bool
 	Shell               string
+	Terminal            string
 	AudioProgram        string
+	SessionFile         string
+	LauncherHistory     string
+	InputHistory        string
+	RegisterUrl         string
 
 	mouse map[string][]mouseCommand
 	key   map[string][]keyCommand
@@ -50,7 +65,19 @@ func newConfig() *Configuration {
 		ShowFyi:         true,
 		ShowErrors:      true,
 		Shell:           "bash",
+		Terminal:        "xterm",
 		AudioProgram:    "aplay",
+		SessionFile:     "session.json",
+		LauncherHistory: "launcher.json",
+		InputHistory:    "input_history.json",
+		RegisterUrl:     "file://register.json",
+
+		Placement:          layout.PlaceSmart,
+		WorkspacePlacement: map[string]layout.Placement{},
+		SnapThreshold:      10,
+		SnapToWindows:      true,
+		EdgeResistance:     20,
+		DragSnap:           true,
 
 		mouse: map[string][]mouseCommand{},
 		key:   map[string][]keyCommand{},
@@ -65,26 +92,30 @@ func loadConfig() (*Configuration, error) {
 	conf := newConfig() // globally defined in wingo.go
 
 	type confFile struct {
-		fpath       string
+		name        string
 		loadSection func(*Configuration, *wini.Data, string)
 	}
 	cfiles := []confFile{
 		{
-			misc.ConfigFile("mouse.wini"),
+			"mouse.wini",
 			(*Configuration).loadMouseConfigSection,
 		},
 		{
-			misc.ConfigFile("key.wini"),
+			"key.wini",
 			(*Configuration).loadKeyConfigSection,
 		},
 		{
-			misc.ConfigFile("options.wini"),
+			"options.wini",
 			(*Configuration).loadOptionsConfigSection,
 		},
 		// FYI hooks.wini is loaded in the hook package.
 	}
 	for _, cfile := range cfiles {
-		cdata, err := wini.Parse(cfile.fpath)
+		fpath, err := misc.ConfigPaths.ConfigFile(cfile.name)
+		if err != nil {
+			return nil, err
+		}
+		cdata, err := wini.Parse(fpath)
 		if err != nil {
 			return nil, err
 		}
@@ -180,6 +211,33 @@ func (conf *Configuration) loadOptionsConfigSection(
 			}
 		case "default_layout":
 			setString(key, &conf.DefaultLayout)
+		case "gaps_inner":
+			setInt(key, &conf.GapsInner)
+		case "gaps_outer":
+			setInt(key, &conf.GapsOuter)
+		case "smart_gaps":
+			setBool(key, &conf.SmartGaps)
+		case "placement":
+			if v, ok := getLastString(key); ok {
+				if p, ok := layout.ParsePlacement(v); ok {
+					conf.Placement = p
+				} else {
+					logger.Warning.Println(
+						key.Err("Unknown placement policy '%s'.", v))
+				}
+			}
+		case "workspace_placement":
+			if v, ok := getLastString(key); ok {
+				conf.loadWorkspacePlacement(key, v)
+			}
+		case "snap_threshold":
+			setInt(key, &conf.SnapThreshold)
+		case "snap_to_windows":
+			setBool(key, &conf.SnapToWindows)
+		case "edge_resistance":
+			setInt(key, &conf.EdgeResistance)
+		case "drag_snap":
+			setBool(key, &conf.DragSnap)
 		case "focus_follows_mouse":
 			setBool(key, &conf.Ffm)
 		case "focus_follows_mouse_focus":
@@ -202,12 +260,52 @@ func (conf *Configuration) loadOptionsConfigSection(
 			setString(key, &conf.ConfirmKey)
 		case "shell":
 			setString(key, &conf.Shell)
+		case "terminal":
+			setString(key, &conf.Terminal)
 		case "audio_play_cmd":
 			setString(key, &conf.AudioProgram)
+		case "session_file":
+			setString(key, &conf.SessionFile)
+		case "launcher_history":
+			setString(key, &conf.LauncherHistory)
+		case "input_history":
+			setString(key, &conf.InputHistory)
+		case "register_url":
+			setString(key, &conf.RegisterUrl)
 		}
 	}
 }
 
+// loadWorkspacePlacement reads a list of "workspace:policy" pairs separated
+// by spaces.
+func (conf *Configuration) loadWorkspacePlacement(key wini.Key, v string) {
+	for _, pair := range strings.Fields(v) {
+		sep := strings.LastIndex(pair, ":")
+		if sep == -1 {
+			logger.Warning.Println(
+				key.Err("Expected 'workspace:policy' but got '%s'.", pair))
+			continue
+		}
+		name, policy := pair[:sep], pair[sep+1:]
+		if p, ok := layout.ParsePlacement(policy); ok {
+			conf.WorkspacePlacement[strings.ToLower(name)] = p
+		} else {
+			logger.Warning.Println(
+				key.Err("Unknown placement policy '%s'.", policy))
+		}
+	}
+}
+
+// placementFor returns the placement policy for the workspace with the name
+// given. If the workspace doesn't have one of its own, the global policy is
+// returned.
+func (conf *Configuration) placementFor(name string) layout.Placement {
+	if p, ok := conf.WorkspacePlacement[strings.ToLower(name)]; ok {
+		return p
+	}
+	return conf.Placement
+}
+
 // strToDirection converts a string representation of a mouse direction
 // to an xgbutil.ewmh constant value. It is case insensitive.
 func strToDirection(s string) uint32 {
@@ -26,7 +26,9 @@ func ewmhNumberOfDesktops() {
 
 func ewmhCurrentDesktop() {
 	ewmh.CurrentDesktopSet(X, uint(workspaceIndex(Workspace())))
-	event.Notify(event.ChangedWorkspace{})
+	event.Notify(event.ChangedWorkspace{
+		WorkspaceInfo: Workspace().EventInfo(),
+	})
 }
 
 func ewmhVisibleDesktops() {
@@ -37,7 +39,11 @@ func ewmhVisibleDesktops() {
 	}
 	ewmh.VisibleDesktopsSet(X, desks)
 
-	event.Notify(event.ChangedVisibleWorkspace{})
+	infos := make([]event.WorkspaceInfo, len(visibles))
+	for i, wrk := range visibles {
+		infos[i] = wrk.EventInfo()
+	}
+	event.Notify(event.ChangedVisibleWorkspace{Visible: infos})
 }
 
 func ewmhDesktopNames() {
@@ -47,7 +53,7 @@ func ewmhDesktopNames() {
 	}
 	ewmh.DesktopNamesSet(X, names)
 
-	event.Notify(event.ChangedWorkspaceNames{})
+	event.Notify(event.ChangedWorkspaceNames{Names: names})
 }
 
 func ewmhDesktopGeometry() {
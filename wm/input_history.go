@@ -0,0 +1,94 @@
+package wm
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/misc"
+	"github.com/xuanmingyi/wingo/prompt"
+)
+
+// inputHistoryMax is the number of entries kept in each input history.
+const inputHistoryMax = 100
+
+// inputHistories are the histories of the input prompt, keyed by name. They
+// are loaded from the "input_history" file when one is first needed.
+var inputHistories map[string]*prompt.InputHistory
+
+// InputHistory returns the input prompt history with the given name, which
+// is created if it doesn't exist yet.
+func InputHistory(name string) *prompt.InputHistory {
+	if inputHistories == nil {
+		loadInputHistories()
+	}
+	if h, ok := inputHistories[name]; ok {
+		return h
+	}
+	h := prompt.NewInputHistory(nil, inputHistoryMax)
+	inputHistories[name] = h
+	return h
+}
+
+// SaveInputHistories writes every input prompt history to the
+// "input_history" file, as a JSON object mapping each name to its entries.
+func SaveInputHistories() {
+	if inputHistories == nil || len(Config.InputHistory) == 0 {
+		return
+	}
+	all := make(map[string][]string, len(inputHistories))
+	for name, h := range inputHistories {
+		all[name] = h.Entries()
+	}
+
+	fpath, err := misc.UserDataFile(Config.InputHistory)
+	if err != nil {
+		logger.Warning.Printf("Could not save input history: %s", err)
+		return
+	}
+	bs, err := json.MarshalIndent(all, "", "\t")
+	if err != nil {
+		logger.Warning.Printf("Could not save input history: %s", err)
+		return
+	}
+	tmp := fpath + ".tmp"
+	if err := ioutil.WriteFile(tmp, bs, 0600); err != nil {
+		logger.Warning.Printf("Could not save input history: %s", err)
+		return
+	}
+	if err := os.Rename(tmp, fpath); err != nil {
+		logger.Warning.Printf("Could not save input history: %s", err)
+	}
+}
+
+// loadInputHistories reads the "input_history" file. If it can't be read,
+// every history starts out empty.
+func loadInputHistories() {
+	inputHistories = make(map[string]*prompt.InputHistory)
+	if len(Config.InputHistory) == 0 {
+		return
+	}
+
+	fpath, err := misc.UserDataFile(Config.InputHistory)
+	if err != nil {
+		logger.Warning.Printf("Could not load input history: %s", err)
+		return
+	}
+	bs, err := ioutil.ReadFile(fpath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warning.Printf("Could not load input history: %s", err)
+		}
+		return
+	}
+	all := make(map[string][]string)
+	if err := json.Unmarshal(bs, &all); err != nil {
+		logger.Warning.Printf("Could not load input history '%s': %s",
+			fpath, err)
+		return
+	}
+	for name, entries := range all {
+		inputHistories[name] = prompt.NewInputHistory(entries, inputHistoryMax)
+	}
+}
@@ -0,0 +1,132 @@
+package wm
+
+import (
+	"os"
+	"time"
+
+	"github.com/BurntSushi/xgbutil/xgraphics"
+
+	"github.com/xuanmingyi/wingo/launcher"
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/misc"
+	"github.com/xuanmingyi/wingo/prompt"
+)
+
+// launcherHistory is the history of the application launcher. It is loaded
+// from the "launcher_history" file the first time the launcher is shown.
+var launcherHistory *launcher.History
+
+// LauncherData is passed to ShowAppLauncher. Selected is called with the
+// entry chosen by the user.
+type LauncherData struct {
+	Selected func(entry *launcher.Entry)
+}
+
+// launcherChoice satisfies the prompt.SelectIconChoice interface for an
+// entry of the application launcher.
+type launcherChoice struct {
+	entry *launcher.Entry
+}
+
+func (lc launcherChoice) SelectText() string {
+	return lc.entry.Name
+}
+
+func (lc launcherChoice) SelectSelected(data interface{}) {
+	if f := data.(LauncherData).Selected; f != nil {
+		f(lc.entry)
+	}
+}
+
+func (lc launcherChoice) SelectHighlighted(data interface{}) {}
+
+func (lc launcherChoice) SelectIcon() *xgraphics.Image {
+	return iconFile(launcher.IconPath(lc.entry.Icon, launcher.DataDirs()))
+}
+
+// ShowAppLauncher shows the select prompt with every application and command
+// that can be launched, ordered by how frequently and how recently they have
+// been launched. The applications and commands are indexed again every time
+// the launcher is shown, so that new ones show up.
+func ShowAppLauncher(data LauncherData) {
+	if Prompts.Slct.Showing() {
+		return
+	}
+
+	// The items of the last time the launcher was shown aren't needed
+	// anymore.
+	for _, item := range Prompts.launcher {
+		item.Destroy()
+	}
+	Prompts.launcher = nil
+
+	entries := launcher.Index()
+	loadLauncherHistory().Sort(entries, time.Now())
+
+	apps := make([]*prompt.SelectItem, 0, len(entries))
+	cmds := make([]*prompt.SelectItem, 0, len(entries))
+	for _, entry := range entries {
+		item := Prompts.Slct.AddChoice(launcherChoice{entry})
+		if entry.Desktop {
+			apps = append(apps, item)
+		} else {
+			cmds = append(cmds, item)
+		}
+		Prompts.launcher = append(Prompts.launcher, item)
+	}
+
+	groups := []*prompt.SelectShowGroup{
+		Prompts.slctApps.ShowGroup(apps),
+		Prompts.slctCmds.ShowGroup(cmds),
+	}
+	Prompts.Slct.Show(Workspace().Geom(), prompt.TabCompleteFuzzy, groups,
+		data)
+}
+
+// LauncherUsed adds entry to the history of the application launcher, and
+// saves the history to the "launcher_history" file.
+func LauncherUsed(entry *launcher.Entry) {
+	history := loadLauncherHistory()
+	history.Add(entry.ID, time.Now())
+
+	if len(Config.LauncherHistory) == 0 {
+		return
+	}
+	fpath, err := misc.UserDataFile(Config.LauncherHistory)
+	if err != nil {
+		logger.Warning.Printf("Could not save launcher history: %s", err)
+		return
+	}
+	if err := history.Save(fpath); err != nil {
+		logger.Warning.Printf("Could not save launcher history: %s", err)
+	}
+}
+
+// loadLauncherHistory returns the history of the application launcher,
+// loading it first if necessary. If the history can't be loaded, an empty
+// history is used.
+func loadLauncherHistory() *launcher.History {
+	if launcherHistory != nil {
+		return launcherHistory
+	}
+	launcherHistory = launcher.NewHistory()
+	if len(Config.LauncherHistory) == 0 {
+		return launcherHistory
+	}
+
+	fpath, err := misc.UserDataFile(Config.LauncherHistory)
+	if err != nil {
+		logger.Warning.Printf("Could not load launcher history: %s", err)
+		return launcherHistory
+	}
+	history, err := launcher.LoadHistory(fpath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warning.Printf("Could not load launcher history '%s': %s",
+				fpath, err)
+		}
+		return launcherHistory
+	}
+	launcherHistory = history
+	return launcherHistory
+}
@@ -0,0 +1,91 @@
+package wm
+
+import (
+	"github.com/BurntSushi/xgbutil/xgraphics"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/prompt"
+)
+
+// MenuItem is a single choice of a menu shown with ShowMenu. Icon is the path
+// of an image file shown next to the text, or empty.
+type MenuItem struct {
+	Text string
+	Icon string
+}
+
+// MenuGroup is a list of menu items under a heading. The heading isn't shown
+// if it's empty.
+type MenuGroup struct {
+	Heading string
+	Items   []MenuItem
+}
+
+// MenuData is passed to ShowMenu. Selected is called with the item chosen by
+// the user.
+type MenuData struct {
+	Selected func(item MenuItem)
+}
+
+// menuChoice satisfies the prompt.SelectIconChoice interface for an item of
+// a menu.
+type menuChoice struct {
+	item MenuItem
+}
+
+func (mc menuChoice) SelectText() string {
+	return mc.item.Text
+}
+
+func (mc menuChoice) SelectSelected(data interface{}) {
+	if f := data.(MenuData).Selected; f != nil {
+		f(mc.item)
+	}
+}
+
+func (mc menuChoice) SelectHighlighted(data interface{}) {}
+
+func (mc menuChoice) SelectIcon() *xgraphics.Image {
+	return iconFile(mc.item.Icon)
+}
+
+// ShowMenu shows the select prompt with the groups of items given. False is
+// returned if the prompt couldn't be shown (i.e., it's already showing or
+// there are no items).
+func ShowMenu(groups []MenuGroup, tabComp int, data MenuData) bool {
+	if Prompts.Slct.Showing() {
+		return false
+	}
+
+	// The items and groups of the last menu shown aren't needed anymore.
+	Prompts.destroyMenu()
+
+	showGroups := make([]*prompt.SelectShowGroup, len(groups))
+	for i, group := range groups {
+		slctGroup := Prompts.Slct.AddGroup(
+			Prompts.Slct.NewStaticGroup(group.Heading))
+		Prompts.menuGroups = append(Prompts.menuGroups, slctGroup)
+
+		items := make([]*prompt.SelectItem, len(group.Items))
+		for j, item := range group.Items {
+			items[j] = Prompts.Slct.AddChoice(menuChoice{item})
+		}
+		Prompts.menu = append(Prompts.menu, items...)
+		showGroups[i] = slctGroup.ShowGroup(items)
+	}
+	return Prompts.Slct.Show(Workspace().Geom(), tabComp, showGroups, data)
+}
+
+// iconFile loads the image file at fpath, to be used as an icon in the select
+// prompt. nil is returned if fpath is empty or the image can't be loaded.
+func iconFile(fpath string) *xgraphics.Image {
+	if len(fpath) == 0 {
+		return nil
+	}
+	img, err := xgraphics.NewFileName(X, fpath)
+	if err != nil {
+		logger.Message.Printf("Could not load icon '%s': %s", fpath, err)
+		return nil
+	}
+	return img
+}
@@ -15,6 +15,14 @@ type AllPrompts struct {
 	Message *prompt.Message
 
 	slctVisible, slctHidden *prompt.SelectGroupItem
+	slctApps, slctCmds      *prompt.SelectGroupItem
+
+	// The items shown the last time the application launcher was shown.
+	launcher []*prompt.SelectItem
+
+	// The items and groups of the last menu shown by ShowMenu.
+	menu       []*prompt.SelectItem
+	menuGroups []*prompt.SelectGroupItem
 }
 
 func newPrompts() AllPrompts {
@@ -46,6 +54,8 @@ func newPrompts() AllPrompts {
 	}
 	ps.slctVisible = ps.Slct.AddGroup(ps.Slct.NewStaticGroup("Visible"))
 	ps.slctHidden = ps.Slct.AddGroup(ps.Slct.NewStaticGroup("Hidden"))
+	ps.slctApps = ps.Slct.AddGroup(ps.Slct.NewStaticGroup("Applications"))
+	ps.slctCmds = ps.Slct.AddGroup(ps.Slct.NewStaticGroup("Commands"))
 	return ps
 }
 
@@ -65,6 +75,40 @@ func filterClient(client Client, activeWrk, visible, iconified bool) bool {
 	return true
 }
 
+// destroy hides every prompt and frees its resources. Any items added to the
+// prompts must be destroyed first.
+func (ps AllPrompts) destroy() {
+	ps.Cycle.Hide()
+	ps.Slct.Hide()
+	ps.Input.Hide()
+	ps.Message.Hide()
+
+	ps.slctVisible.Destroy()
+	ps.slctHidden.Destroy()
+	ps.slctApps.Destroy()
+	ps.slctCmds.Destroy()
+	for _, item := range ps.launcher {
+		item.Destroy()
+	}
+	ps.destroyMenu()
+
+	ps.Cycle.Destroy()
+	ps.Slct.Destroy()
+	ps.Input.Destroy()
+	ps.Message.Destroy()
+}
+
+// destroyMenu destroys the items and groups of the last menu shown.
+func (ps *AllPrompts) destroyMenu() {
+	for _, item := range ps.menu {
+		item.Destroy()
+	}
+	for _, group := range ps.menuGroups {
+		group.Destroy()
+	}
+	ps.menu, ps.menuGroups = nil, nil
+}
+
 func PopupError(format string, vals ...interface{}) {
 	if !Config.ShowErrors {
 		return
@@ -0,0 +1,64 @@
+package wm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/misc"
+	"github.com/xuanmingyi/wingo/register"
+)
+
+// Register is a persistent key/value store available to commands, hooks and
+// scripts. It is nil if no register could be opened.
+var Register *register.Register
+
+// openRegister opens the register database at the URL given by the
+// "register_url" option. Failure only results in a warning, since the
+// register isn't essential for Wingo to run.
+func openRegister() {
+	if len(Config.RegisterUrl) == 0 {
+		return
+	}
+	url, err := registerUrl(Config.RegisterUrl)
+	if err != nil {
+		logger.Warning.Printf("Could not open register '%s': %s",
+			Config.RegisterUrl, err)
+		return
+	}
+
+	reg := new(register.Register)
+	if err := reg.Open(url); err != nil {
+		logger.Warning.Printf("Could not open register '%s': %s", url, err)
+		return
+	}
+	Register = reg
+	logger.Message.Printf("Opened register '%s'.", url)
+}
+
+// CloseRegister closes the register database, if one is open.
+func CloseRegister() {
+	if Register != nil {
+		Register.Close()
+		Register = nil
+	}
+}
+
+// registerUrl resolves a relative file path in a register URL with respect
+// to the user's data directory. i.e., "file://register.json" becomes
+// "file:///home/user/.local/share/wingo/register.json".
+func registerUrl(url string) (string, error) {
+	i := strings.Index(url, "://")
+	if i == -1 {
+		return "", fmt.Errorf("'%s' is not of the form 'scheme://path'", url)
+	}
+	scheme, fpath := url[:i], url[i+3:]
+	if len(fpath) == 0 {
+		return url, nil
+	}
+	fpath, err := misc.UserDataFile(fpath)
+	if err != nil {
+		return "", err
+	}
+	return scheme + "://" + fpath, nil
+}
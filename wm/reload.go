@@ -0,0 +1,94 @@
+package wm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/xgbutil/keybind"
+	"github.com/BurntSushi/xgbutil/mousebind"
+
+	"github.com/xuanmingyi/wingo/hook"
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/misc"
+	"github.com/xuanmingyi/wingo/rules"
+)
+
+// ReloadConfig re-reads every configuration file and applies it without
+// restarting Wingo. Key and mouse bindings are re-attached, frames and
+// prompts are rebuilt with the new theme, and hooks and rules are reloaded.
+//
+// If the configuration or the theme can't be found or parsed, an error is
+// returned and nothing is changed. Similarly, hooks and rules are only
+// replaced if their files can be parsed (a missing rules file means no
+// rules). The gaps given by the "gaps_inner", "gaps_outer" and "smart_gaps"
+// options are applied to every workspace, except for gaps set with the
+// SetGaps command. Likewise, the "placement" and "workspace_placement"
+// options are applied to every workspace, except for policies set with the
+// SetPlacement command. Options that are only read at startup (like the
+// initial workspaces) still need a restart to take effect.
+//
+// ReloadConfig must be called from the main X event loop.
+func ReloadConfig() error {
+	conf, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("Could not load configuration: %s", err)
+	}
+	theme, err := loadTheme()
+	if err != nil {
+		return fmt.Errorf("Could not load theme: %s", err)
+	}
+
+	// Key bindings for CycleClient{Next,Prev} are also attached to the
+	// dummy window.
+	keybind.Detach(X, Root.Id)
+	keybind.Detach(X, X.Dummy())
+	mousebind.Detach(X, Root.Id)
+
+	Config, Theme = conf, theme
+	keybindings()
+	rootMouseSetup()
+
+	Heads.Workspaces.SetDefaultGaps(layout.Gaps{
+		Inner: Config.GapsInner,
+		Outer: Config.GapsOuter,
+		Smart: Config.SmartGaps,
+	})
+	for _, wrk := range Heads.Workspaces.Wrks {
+		wrk.SetDefaultPlacement(Config.placementFor(wrk.Name))
+	}
+
+	// Prompt items of clients and workspaces have to be moved to the new
+	// prompts before the old prompts can be destroyed.
+	oldPrompts := Prompts
+	Prompts = newPrompts()
+	for _, wrk := range Heads.Workspaces.Wrks {
+		wrk.PromptSlctGroup.Destroy()
+		wrk.PromptSlctItem.Destroy()
+		wrk.PromptSlctGroup = Prompts.Slct.AddGroup(wrk)
+		wrk.PromptSlctItem = Prompts.Slct.AddChoice(wrk)
+	}
+	for _, client := range Clients {
+		client.Reload()
+	}
+	oldPrompts.destroy()
+
+	errs := make([]string, 0)
+	if fpath, err := misc.ConfigPaths.ConfigFile("hooks.wini"); err != nil {
+		errs = append(errs, fmt.Sprintf("Could not load hooks: %s", err))
+	} else if err := hook.Reload(fpath); err != nil {
+		errs = append(errs, fmt.Sprintf("Could not load hooks: %s", err))
+	}
+
+	// A missing rules file just means that there are no rules.
+	rulesPath, _ := misc.ConfigPaths.ConfigFile("rules.wini")
+	if err := rules.Reload(rulesPath); err != nil {
+		errs = append(errs, fmt.Sprintf("Could not load rules: %s", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	logger.Message.Println("Configuration reloaded.")
+	return nil
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/xuanmingyi/wingo/event"
 	"github.com/xuanmingyi/wingo/focus"
 	"github.com/xuanmingyi/wingo/heads"
+	"github.com/xuanmingyi/wingo/hook"
+	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/workspace"
 )
@@ -30,6 +32,7 @@ var (
 	Config     *Configuration
 	Theme      *ThemeConfig
 	StickyWrk  *workspace.Sticky
+	ScratchWrk *workspace.Scratchpad
 	gribbleEnv *gribble.Environment
 	cmdHacks   CommandHacks
 	ShapeExt   bool
@@ -62,7 +65,13 @@ func Initialize(x *xgbutil.XUtil,
 	Clients = make(ClientList, 0, 50)
 	Prompts = newPrompts()
 
-	Heads = heads.NewHeads(X, Config.DefaultLayout)
+	openRegister()
+
+	Heads = heads.NewHeads(X, Config.DefaultLayout, layout.Gaps{
+		Inner: Config.GapsInner,
+		Outer: Config.GapsOuter,
+		Smart: Config.SmartGaps,
+	}, newTabBar)
 
 	// If _NET_DESKTOP_NAMES is set, let's use workspaces from that instead.
 	if names, _ := ewmh.DesktopNamesGet(X); len(names) > 0 {
@@ -85,6 +94,7 @@ func Initialize(x *xgbutil.XUtil,
 	rootMouseSetup()
 
 	StickyWrk = Heads.Workspaces.NewSticky()
+	ScratchWrk = Heads.Workspaces.NewScratchpad()
 
 	err = shape.Init(X.Conn())
 	if err != nil {
@@ -159,6 +169,10 @@ func SetWorkspace(wrk *workspace.Workspace, greedy bool) {
 	wrk.Activate(greedy)
 	if old != Workspace() {
 		FYI("%s", wrk)
+		hook.Fire(hook.WorkspaceChanged, hook.Args{
+			Workspace: wrk.Name,
+			Head:      fmt.Sprintf("%d", Heads.VisibleIndex(wrk)),
+		})
 	}
 
 	ewmhVisibleDesktops()
@@ -207,6 +221,7 @@ func AddWorkspace(name string) error {
 		return fmt.Errorf("a workspace with name '%s' already exists.", name)
 	}
 	wrk := Heads.NewWorkspace(name)
+	wrk.SetDefaultPlacement(Config.placementFor(name))
 	wrk.PromptSlctGroup = Prompts.Slct.AddGroup(wrk)
 	wrk.PromptSlctItem = Prompts.Slct.AddChoice(wrk)
 
@@ -217,7 +232,7 @@ func AddWorkspace(name string) error {
 	ewmhVisibleDesktops()
 	Heads.EwmhWorkarea()
 
-	event.Notify(event.AddedWorkspace{wrk.Name})
+	event.Notify(event.AddedWorkspace{WorkspaceInfo: wrk.EventInfo()})
 	return nil
 }
 
@@ -0,0 +1,58 @@
+package wm
+
+import (
+	"github.com/BurntSushi/xgbutil/xgraphics"
+	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/frame"
+	"github.com/xuanmingyi/wingo/layout"
+)
+
+// tabClient is the method set needed from a client to draw its tab.
+type tabClient interface {
+	Name() string
+	Icon(width, height int) *xgraphics.Image
+}
+
+// tabBar draws the tab bar of a Tabbed layout with the Full frame theme.
+type tabBar struct {
+	*frame.TabBar
+	clients []layout.Client
+}
+
+// newTabBar is given to every workspace to create the tab bar of its Tabbed
+// layout.
+func newTabBar() layout.TabBar {
+	tb := &tabBar{}
+	tb.TabBar = frame.NewTabBar(X, Theme.Full.FrameTheme(), tb.clicked)
+	return tb
+}
+
+func (tb *tabBar) Update(geom xrect.Rect, clients []layout.Client,
+	active int) {
+
+	// Always use the latest theme, since it may have been reloaded.
+	theme := Theme.Full.FrameTheme()
+	tb.SetTheme(theme)
+
+	size := theme.TitleSize - 4
+	tabs := make([]frame.Tab, len(clients))
+	for i, c := range clients {
+		tabs[i].Name = c.String()
+		if tc, ok := c.(tabClient); ok {
+			tabs[i].Name = tc.Name()
+			if size > 0 {
+				tabs[i].Icon = tc.Icon(size, size)
+			}
+		}
+	}
+	tb.clients = clients
+	tb.TabBar.Update(geom, tabs, active)
+}
+
+func (tb *tabBar) clicked(i int) {
+	if i < len(tb.clients) {
+		tb.clients[i].Focus()
+		tb.clients[i].Raise()
+	}
+}
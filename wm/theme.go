@@ -110,6 +110,9 @@ type ThemePrompt struct {
 	selectActiveBgColor   render.Color
 	selectActiveFontColor render.Color
 
+	selectMatchFontColor       render.Color
+	selectActiveMatchFontColor render.Color
+
 	selectGroupBgColor   render.Color
 	selectGroupFont      *truetype.Font
 	selectGroupFontSize  float64
@@ -147,6 +150,9 @@ func (tp ThemePrompt) SelectTheme() *prompt.SelectTheme {
 		GroupFontSize:   tp.selectGroupFontSize,
 		GroupFontColor:  tp.selectGroupFontColor,
 		GroupSpacing:    15,
+
+		MatchFontColor:       tp.selectMatchFontColor,
+		ActiveMatchFontColor: tp.selectActiveMatchFontColor,
 	}
 }
 
@@ -236,6 +242,9 @@ func newTheme() *ThemeConfig {
 			selectGroupFont:       builtInFont(),
 			selectGroupFontSize:   25.0,
 			selectGroupFontColor:  render.NewColor(0x0),
+
+			selectMatchFontColor:       render.NewColor(0x3366ff),
+			selectActiveMatchFontColor: render.NewColor(0x3366ff),
 		},
 	}
 }
@@ -243,7 +252,11 @@ func newTheme() *ThemeConfig {
 func loadTheme() (*ThemeConfig, error) {
 	theme := newTheme()
 
-	tdata, err := wini.Parse(misc.ConfigFile("theme.wini"))
+	fpath, err := misc.ConfigPaths.ConfigFile("theme.wini")
+	if err != nil {
+		return nil, err
+	}
+	tdata, err := wini.Parse(fpath)
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +436,10 @@ func loadPromptOption(theme *ThemeConfig, k wini.Key) {
 		setNoGradient(k, &theme.Prompt.selectActiveFontColor)
 	case "select_active_bg_color":
 		setNoGradient(k, &theme.Prompt.selectActiveBgColor)
+	case "select_match_font_color":
+		setNoGradient(k, &theme.Prompt.selectMatchFontColor)
+	case "select_active_match_font_color":
+		setNoGradient(k, &theme.Prompt.selectActiveMatchFontColor)
 	case "select_group_bg_color":
 		setNoGradient(k, &theme.Prompt.selectGroupBgColor)
 	case "select_group_font":
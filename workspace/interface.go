@@ -73,3 +73,50 @@ func (wrk *Sticky) IconifyToggle(c Client) {
 func (wrk *Sticky) Layout(c Client) layout.Layout {
 	return wrk.floater
 }
+
+// Scratchpad is a hidden pseudo-workspace. Clients sent to it are unmapped
+// until they are brought back to a real workspace. Like Sticky, it has its
+// own floating layout and keeps no list of clients.
+type Scratchpad struct {
+	X       *xgbutil.XUtil
+	floater layout.Floater
+}
+
+func (wrks *Workspaces) NewScratchpad() *Scratchpad {
+	return &Scratchpad{wrks.X, layout.NewFloating()}
+}
+
+func (wrk *Scratchpad) String() string {
+	return "Scratchpad"
+}
+
+func (wrk *Scratchpad) LayoutName() string {
+	return wrk.floater.Name()
+}
+
+func (wrk *Scratchpad) Geom() xrect.Rect {
+	return xwindow.RootGeometry(wrk.X)
+}
+
+func (wrk *Scratchpad) HeadGeom() xrect.Rect {
+	return xwindow.RootGeometry(wrk.X)
+}
+
+func (wrk *Scratchpad) IsActive() bool {
+	return false
+}
+
+func (wrk *Scratchpad) IsVisible() bool {
+	return false
+}
+
+func (wrk *Scratchpad) Add(c Client) {}
+
+func (wrk *Scratchpad) Remove(c Client) {}
+
+// Clients in the scratchpad are always hidden, so they can't be iconified.
+func (wrk *Scratchpad) IconifyToggle(c Client) {}
+
+func (wrk *Scratchpad) Layout(c Client) layout.Layout {
+	return wrk.floater
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/BurntSushi/xgbutil/xrect"
 
 	"github.com/xuanmingyi/wingo/event"
+	"github.com/xuanmingyi/wingo/hook"
 	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/prompt"
@@ -26,6 +27,15 @@ type Workspace struct {
 
 	autoTilers   []layout.AutoTiler
 	curAutoTiler int
+	gaps         layout.Gaps
+
+	// customGaps is true once the gaps have been set with SetGaps, so that
+	// they're kept when the default gaps change.
+	customGaps bool
+
+	// customPlacement is true once the placement policy has been set with
+	// SetPlacement, so that it's kept when the default policy changes.
+	customPlacement bool
 
 	PromptSlctGroup *prompt.SelectGroupItem
 	PromptSlctItem  *prompt.SelectItem
@@ -52,7 +62,14 @@ func (wrks *Workspaces) NewWorkspace(name string) *Workspace {
 		layout.NewVertical(),
 		layout.NewHorizontal(),
 		layout.NewMaximized(),
+		layout.NewManual(),
+		layout.NewGrid(),
+		layout.NewSpiral(),
+		layout.NewDwindle(),
+		layout.NewCenteredMaster(),
+		layout.NewTabbed(wrks.newTabBar()),
 	}
+	wrk.setGaps(wrks.defaultGaps)
 
 	if state, index := wrk.findLayout(wrks.defaultLayout); state != -1 {
 		switch state {
@@ -107,6 +124,16 @@ func (wrk *Workspace) IsVisible() bool {
 	return wrk.all.heads.Geom(wrk) != nil
 }
 
+// EventInfo describes this workspace for event subscribers.
+func (wrk *Workspace) EventInfo() event.WorkspaceInfo {
+	return event.WorkspaceInfo{
+		Name:   wrk.Name,
+		Head:   wrk.all.heads.VisibleIndex(wrk),
+		Layout: wrk.LayoutName(),
+		Active: wrk.IsActive(),
+	}
+}
+
 func (wrk *Workspace) Activate(greedy bool) {
 	if wrk.IsActive() {
 		return
@@ -230,6 +257,9 @@ func (wrk *Workspace) Show() {
 }
 
 func (wrk *Workspace) Hide() {
+	if wrk.State == AutoTiling {
+		wrk.LayoutAutoTiler().Unplace()
+	}
 	wrk.setGeom(nil)
 	for _, c := range wrk.Clients {
 		if c.Workspace() == wrk {
@@ -340,6 +370,27 @@ func (wrk *Workspace) LayoutAutoTiler() layout.AutoTiler {
 	return wrk.autoTilers[wrk.curAutoTiler]
 }
 
+// AutoTilers returns every tiling layout of this workspace, whether it's in
+// use or not.
+func (wrk *Workspace) AutoTilers() []layout.AutoTiler {
+	return wrk.autoTilers
+}
+
+// SetLayoutState restores the state of the tiling layout named name. If that
+// layout is in use, the workspace is placed again. False is returned if
+// there is no tiling layout with that name.
+func (wrk *Workspace) SetLayoutState(name string, s layout.State) bool {
+	state, index := wrk.findLayout(name)
+	if state != AutoTiling {
+		return false
+	}
+	wrk.autoTilers[index].SetState(s)
+	if wrk.State == AutoTiling && wrk.curAutoTiler == index {
+		wrk.Place()
+	}
+	return true
+}
+
 func (wrk *Workspace) addToFloaters(c Client) {
 	for _, floater := range wrk.floaters {
 		floater.Add(c)
@@ -367,12 +418,74 @@ func (wrk *Workspace) removeFromTilers(c Client) {
 	}
 }
 
+// Placement returns the policy used to place new floating clients on this
+// workspace.
+func (wrk *Workspace) Placement() layout.Placement {
+	return wrk.LayoutFloater().Placement()
+}
+
+// SetPlacement sets the policy used to place new floating clients on this
+// workspace. This policy is kept when the default policy changes. (See
+// SetDefaultPlacement.)
+func (wrk *Workspace) SetPlacement(p layout.Placement) {
+	wrk.customPlacement = true
+	wrk.setPlacement(p)
+}
+
+// SetDefaultPlacement is like SetPlacement, but for a policy that comes from
+// the configuration. It has no effect if the policy of this workspace was set
+// with SetPlacement.
+func (wrk *Workspace) SetDefaultPlacement(p layout.Placement) {
+	if !wrk.customPlacement {
+		wrk.setPlacement(p)
+	}
+}
+
+func (wrk *Workspace) setPlacement(p layout.Placement) {
+	for _, lay := range wrk.floaters {
+		lay.SetPlacement(p)
+	}
+}
+
+// Gaps returns the gaps used by the tiling layouts of this workspace.
+func (wrk *Workspace) Gaps() layout.Gaps {
+	return wrk.gaps
+}
+
+// SetGaps sets the gaps used by every tiling layout of this workspace, and
+// places the workspace again so they take effect. These gaps are kept when
+// the default gaps change. (See SetDefaultGaps.)
+func (wrk *Workspace) SetGaps(gaps layout.Gaps) {
+	wrk.customGaps = true
+	wrk.setGaps(gaps)
+	wrk.Place()
+}
+
+// SetDefaultGaps is like SetGaps, but for gaps that come from the
+// configuration. If the gaps of this workspace were set with SetGaps, only
+// whether smart gaps are used changes.
+func (wrk *Workspace) SetDefaultGaps(gaps layout.Gaps) {
+	if wrk.customGaps {
+		gaps.Inner, gaps.Outer = wrk.gaps.Inner, wrk.gaps.Outer
+	}
+	wrk.setGaps(gaps)
+	wrk.Place()
+}
+
+func (wrk *Workspace) setGaps(gaps layout.Gaps) {
+	wrk.gaps = gaps
+	for _, lay := range wrk.autoTilers {
+		lay.SetGaps(gaps)
+	}
+}
+
 func (wrk *Workspace) AutoCycle() {
 	if wrk.State == AutoTiling {
+		wrk.LayoutAutoTiler().Unplace()
 		wrk.curAutoTiler = (wrk.curAutoTiler + 1) % len(wrk.autoTilers)
 		wrk.LayoutAutoTiler().Place()
 
-		event.Notify(event.ChangedLayout{wrk.Name})
+		wrk.notifyLayout()
 	}
 }
 
@@ -395,6 +508,9 @@ func (wrk *Workspace) SetLayout(name string) {
 		wrk.curFloater = index
 		wrk.LayoutStateSet(Floating)
 	case AutoTiling:
+		if wrk.State == AutoTiling && wrk.curAutoTiler != index {
+			wrk.LayoutAutoTiler().Unplace()
+		}
 		wrk.curAutoTiler = index
 		wrk.LayoutStateSet(AutoTiling)
 	case -1: // couldn't find layout with name 'name'
@@ -485,7 +601,20 @@ func (wrk *Workspace) LayoutStateSet(state int) {
 		panic("Layout state not implemented.")
 	}
 
-	event.Notify(event.ChangedLayout{wrk.Name})
+	wrk.notifyLayout()
+}
+
+func (wrk *Workspace) notifyLayout() {
+	head := wrk.all.heads.VisibleIndex(wrk)
+	event.Notify(event.ChangedLayout{
+		Workspace: wrk.Name,
+		Layout:    wrk.LayoutName(),
+		Head:      head,
+	})
+	hook.Fire(hook.LayoutChanged, hook.Args{
+		Workspace: wrk.Name,
+		Head:      fmt.Sprintf("%d", head),
+	})
 }
 
 func (wrk *Workspace) SelectGroupText() string {
@@ -6,6 +6,8 @@ import (
 
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/layout"
 )
 
 const (
@@ -18,6 +20,7 @@ type Heads interface {
 	ActiveWorkspace() *Workspace
 	VisibleWorkspaces() []*Workspace
 	IsActive(wrk *Workspace) bool
+	VisibleIndex(wrk *Workspace) int
 	Geom(wrk *Workspace) xrect.Rect
 	HeadGeom(wrk *Workspace) xrect.Rect
 
@@ -30,16 +33,29 @@ type Workspaces struct {
 	Wrks          []*Workspace
 	heads         Heads
 	defaultLayout string
+	defaultGaps   layout.Gaps
+	newTabBar     func() layout.TabBar
 }
 
-func NewWorkspaces(
-	X *xgbutil.XUtil, heads Heads, defaultLayout string) *Workspaces {
+func NewWorkspaces(X *xgbutil.XUtil, heads Heads, defaultLayout string,
+	defaultGaps layout.Gaps, newTabBar func() layout.TabBar) *Workspaces {
 
 	return &Workspaces{
 		X:             X,
 		Wrks:          make([]*Workspace, 0, 1),
 		heads:         heads,
 		defaultLayout: defaultLayout,
+		defaultGaps:   defaultGaps,
+		newTabBar:     newTabBar,
+	}
+}
+
+// SetDefaultGaps sets the gaps given to new workspaces, and gives them to
+// every existing workspace with SetDefaultGaps.
+func (wrks *Workspaces) SetDefaultGaps(gaps layout.Gaps) {
+	wrks.defaultGaps = gaps
+	for _, wrk := range wrks.Wrks {
+		wrk.SetDefaultGaps(gaps)
 	}
 }
 
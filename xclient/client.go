@@ -50,6 +50,7 @@ type Client struct {
 	fullscreen  bool
 	iconified   bool
 	sticky      bool // Belongs to no workspace.
+	scratchpad  bool // Shown and hidden with ScratchpadToggle.
 	skipTaskbar bool
 	skipPager   bool
 
@@ -74,9 +75,13 @@ type Client struct {
 	moving, resizing bool
 
 	dragGeom  xrect.Rect
+	snap      *snapper
 	hadStruts bool
 	shaped    bool
 
+	// notifiedGeom is the frame geometry last sent to event subscribers.
+	notifiedGeom xrect.Rect
+
 	attnQuit  chan struct{}
 	demanding bool
 }
@@ -89,7 +94,7 @@ func (c *Client) Map() {
 	c.frame.Map()
 	icccm.WmStateSet(wm.X, c.Id(), &icccm.WmState{State: icccm.StateNormal})
 
-	event.Notify(event.MappedClient{c.Id()})
+	event.Notify(event.MappedClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) Unmap() {
@@ -101,7 +106,7 @@ func (c *Client) Unmap() {
 	c.win.Unmap()
 	icccm.WmStateSet(wm.X, c.Id(), &icccm.WmState{State: icccm.StateIconic})
 
-	event.Notify(event.UnmappedClient{c.Id()})
+	event.Notify(event.UnmappedClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) Close() {
@@ -156,6 +161,9 @@ func (c *Client) FireHook(hk hook.Type) {
 	args := hook.Args{
 		Client: fmt.Sprintf("%d", c.Id()),
 	}
+	if c.workspace != nil {
+		args.Workspace = c.workspace.String()
+	}
 	hook.Fire(hk, args)
 }
 
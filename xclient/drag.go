@@ -8,6 +8,8 @@ import (
 
 	"github.com/xuanmingyi/wingo/cursors"
 	"github.com/xuanmingyi/wingo/frame"
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/wm"
 )
 
 func (c *Client) DragGeom() xrect.Rect {
@@ -28,19 +30,32 @@ func (c *Client) DragMoveBegin(rx, ry, ex, ey int) bool {
 	f.Parent().Geometry()
 
 	c.dragGeom = xrect.New(xrect.Pieces(f.Geom()))
+	c.snap = c.newSnapper()
 	return true
 }
 
 func (c *Client) DragMoveStep(rx, ry, ex, ey int) {
 	f := c.frame
 	moving := f.MovingState()
-	newx := c.dragGeom.X() + rx - moving.RootX
-	newy := c.dragGeom.Y() + ry - moving.RootY
+	c.unsnap(rx)
+	c.snap.rawX += rx - moving.RootX
+	c.snap.rawY += ry - moving.RootY
 	moving.RootX, moving.RootY = rx, ry
 
+	newx, newy := c.snap.move(c.snap.rawX, c.snap.rawY,
+		c.dragGeom.Width(), c.dragGeom.Height())
+
 	c.dragGeom.XSet(newx)
 	c.dragGeom.YSet(newy)
 	c.LayoutMove(newx, newy)
+
+	if _, ok := c.Layout().(layout.Floater); ok && wm.Config.DragSnap {
+		if drop := dragSnapRegion(rx, ry); drop != nil {
+			c.snap.showPreview(c, drop)
+		} else {
+			c.snap.hidePreview()
+		}
+	}
 }
 
 func (c *Client) DragMoveEnd(rx, ry, ex, ey int) {
@@ -51,6 +66,13 @@ func (c *Client) DragMoveEnd(rx, ry, ex, ey int) {
 	moving.Moving = false
 	moving.RootX, moving.RootY = 0, 0
 	c.dragGeom = nil
+
+	c.snap.destroyPreview()
+	if c.snap.drop != nil {
+		c.snapTo(c.snap.drop)
+	}
+	c.snap = nil
+	c.notifyGeometry()
 }
 
 func (c *Client) DragResizeBegin(direction uint32,
@@ -167,6 +189,7 @@ func (c *Client) DragResizeBegin(direction uint32,
 		dir == ewmh.SizeBottom || dir == ewmh.SizeBottomLeft
 
 	c.dragGeom = xrect.New(xrect.Pieces(f.Geom()))
+	c.snap = c.newSnapper()
 
 	return true, cursor
 }
@@ -192,7 +215,21 @@ func (c *Client) DragResizeStep(rx, ry, ex, ey int) {
 		} else {
 			neww = resizing.Width + diffx
 		}
+	}
+	if resizing.Hs {
+		if resizing.Ys {
+			newh = resizing.Height - diffy
+		} else {
+			newh = resizing.Height + diffy
+		}
+	}
 
+	// Snap the edges being dragged before validating the new size, so that
+	// the size hints of the client always win.
+	newx, newy, neww, newh = c.snap.resize(newx, newy, neww, newh,
+		resizing.Xs, resizing.Ws, resizing.Ys, resizing.Hs)
+
+	if resizing.Ws {
 		leftRight := f.Left() + f.Right()
 		validw = c.ValidateWidth(neww-leftRight) + leftRight
 
@@ -203,12 +240,6 @@ func (c *Client) DragResizeStep(rx, ry, ex, ey int) {
 		}
 	}
 	if resizing.Hs {
-		if resizing.Ys {
-			newh = resizing.Height - diffy
-		} else {
-			newh = resizing.Height + diffy
-		}
-
 		topBot := f.Top() + f.Bottom()
 		validh = c.ValidateHeight(newh-topBot) + topBot
 
@@ -245,4 +276,6 @@ func (c *Client) DragResizeEnd(rx, ry, ex, ey int) {
 	resizing.Xs, resizing.Ys = false, false
 	resizing.Ws, resizing.Hs = false, false
 	c.dragGeom = nil
+	c.snap = nil
+	c.notifyGeometry()
 }
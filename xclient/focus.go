@@ -23,9 +23,10 @@ func (c *Client) Focused() {
 	focus.SetFocus(c)
 	ewmh.ActiveWindowSet(wm.X, c.Id())
 	c.addState("_NET_WM_STATE_FOCUSED")
+	c.updateTabs()
 
-	event.Notify(event.FocusedClient{c.Id()})
-	event.Notify(event.ChangedActiveClient{c.Id()})
+	event.Notify(event.FocusedClient{ClientInfo: c.EventInfo()})
+	event.Notify(event.ChangedActiveClient{ClientInfo: c.EventInfo()})
 	c.FireHook(hook.Focused)
 }
 
@@ -38,8 +39,8 @@ func (c *Client) Unfocused() {
 	c.removeState("_NET_WM_STATE_FOCUSED")
 
 	if wasFocused {
-		event.Notify(event.UnfocusedClient{c.Id()})
-		event.Notify(event.ChangedActiveClient{0})
+		event.Notify(event.UnfocusedClient{ClientInfo: c.EventInfo()})
+		event.Notify(event.ChangedActiveClient{})
 		c.FireHook(hook.Unfocused)
 	}
 }
@@ -51,7 +52,10 @@ func (c *Client) PrepareForFocus() {
 	// It is possible to be both. Check for both and remedy the situation.
 	// We must check for (1) before (2), since a window cannot toggle its
 	// iconification status if its workspace is not the current workspace.
-	if c.workspace != wm.Workspace() {
+	// A client in the scratchpad is simply shown on the active workspace.
+	if c.workspace == wm.ScratchWrk {
+		c.scratchpadShow()
+	} else if c.workspace != wm.Workspace() {
 		// This isn't applicable if we're sticky.
 		if wrk, ok := c.workspace.(*workspace.Workspace); ok {
 			wm.SetWorkspace(wrk, false)
@@ -270,6 +270,17 @@ func (cf clientFrames) set(f frame.Frame) {
 	cf.client.refreshExtents()
 }
 
+// reload rebuilds every frame with the current theme, keeping the frame
+// that is in use.
+func (cf clientFrames) reload() {
+	cf.slim.SetTheme(wm.Theme.Slim.FrameTheme())
+	cf.borders.SetTheme(wm.Theme.Borders.FrameTheme())
+	cf.full.SetTheme(wm.Theme.Full.FrameTheme())
+
+	frame.Reset(cf.client.frame)
+	cf.client.refreshExtents()
+}
+
 // destroy will destroy all resources associated with any frames created for
 // this client.
 func (cf clientFrames) destroy() {
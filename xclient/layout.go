@@ -35,6 +35,16 @@ func (c *Client) Layout() layout.Layout {
 	return c.workspace.Layout(c)
 }
 
+// updateTabs redraws the tab bar of the client's layout, if it has one.
+func (c *Client) updateTabs() {
+	if c.workspace == nil {
+		return
+	}
+	if lay, ok := c.Layout().(*layout.Tabbed); ok {
+		lay.UpdateTabs()
+	}
+}
+
 func (c *Client) LayoutMROpt(flags, x, y, width, height int) {
 	c.resizing = true
 	c.Layout().MROpt(c, flags, x, y, width, height)
@@ -74,6 +84,7 @@ func (c *Client) FrameTile() {
 
 func (c *Client) MROpt(validate bool, flags, x, y, w, h int) {
 	c.frame.MROpt(validate, flags, x, y, w, h)
+	c.notifyGeometry()
 
 	// As per ICCCM 4.1.5, a window that has been moved but not resized must
 	// receive a synthetic ConfigureNotify event.
@@ -86,14 +97,17 @@ func (c *Client) MROpt(validate bool, flags, x, y, w, h int) {
 
 func (c *Client) MoveResize(x, y, width, height int) {
 	c.frame.MoveResize(false, x, y, width, height)
+	c.notifyGeometry()
 }
 
 func (c *Client) MoveResizeValid(x, y, width, height int) {
 	c.frame.MoveResize(true, x, y, width, height)
+	c.notifyGeometry()
 }
 
 func (c *Client) Move(x, y int) {
 	c.frame.Move(x, y)
+	c.notifyGeometry()
 
 	// As per ICCCM 4.1.5, a window that has been moved but not resized must
 	// receive a synthetic ConfigureNotify event.
@@ -102,4 +116,5 @@ func (c *Client) Move(x, y int) {
 
 func (c *Client) Resize(validate bool, width, height int) {
 	c.frame.Resize(validate, width, height)
+	c.notifyGeometry()
 }
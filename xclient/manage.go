@@ -17,6 +17,7 @@ import (
 	"github.com/xuanmingyi/wingo/frame"
 	"github.com/xuanmingyi/wingo/heads"
 	"github.com/xuanmingyi/wingo/hook"
+	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/stack"
 	"github.com/xuanmingyi/wingo/wm"
@@ -74,7 +75,7 @@ func New(id xproto.Window) *Client {
 	// If someone really wants it, we can add a new "startup_managed" hook
 	// or something.
 	if !wm.Startup {
-		event.Notify(event.ManagedClient{c.Id()})
+		event.Notify(event.ManagedClient{ClientInfo: c.EventInfo()})
 		c.FireHook(hook.Managed)
 	}
 	if !c.iconified {
@@ -115,8 +116,28 @@ func (c *Client) manage() {
 
 	presumedWorkspace := c.findPresumedWorkspace()
 
+	// Rules from rules.wini may say where the client goes...
+	rule := c.rulesMatch()
+	if rule != nil {
+		presumedWorkspace = c.rulesWorkspace(rule, presumedWorkspace)
+	}
+
+	// ... but if this client was saved in a session, it goes back to where
+	// it was.
+	saved := c.sessionTake()
+	if saved != nil {
+		presumedWorkspace = c.sessionWorkspace(saved, presumedWorkspace)
+	}
+
 	c.moveToProperHead(presumedWorkspace)
-	c.maybeInitPlace(presumedWorkspace)
+	place := true
+	if rule != nil {
+		place = !c.rulesInit(rule, presumedWorkspace)
+	}
+	c.maybeInitPlace(presumedWorkspace, place)
+	if saved != nil {
+		c.sessionInit(saved)
+	}
 	wm.AddClient(c)
 	c.maybeAddToFocusStack()
 	c.Raise()
@@ -130,6 +151,13 @@ func (c *Client) manage() {
 	}
 
 	c.updateInitStates()
+	if rule != nil {
+		c.rulesFinish(rule)
+	}
+	if saved != nil {
+		c.sessionFinish(saved)
+	}
+	c.maybeScratchpad()
 	ewmh.WmAllowedActionsSet(wm.X, c.Id(), allowedActions)
 
 	err := xproto.ChangeSaveSetChecked(
@@ -144,7 +172,11 @@ func (c *Client) manage() {
 	<-promptDone
 }
 
-func (c *Client) maybeInitPlace(presumedWorkspace workspace.Workspacer) {
+// maybeInitPlace gives a new client its initial placement, unless place is
+// false or the client shouldn't be placed.
+func (c *Client) maybeInitPlace(presumedWorkspace workspace.Workspacer,
+	place bool) {
+
 	// This is a hack. Before a client gets sucked into some layout, we
 	// always want to have some floating state to fall back on to. However,
 	// by the time we're "allowed" to save the client's state, it will have
@@ -186,6 +218,10 @@ func (c *Client) maybeInitPlace(presumedWorkspace workspace.Workspacer) {
 		}
 	}()
 
+	if !place {
+		return
+	}
+
 	// Any client that isn't normal doesn't get placed.
 	// Let it do what it do, baby.
 	if c.PrimaryType() != TypeNormal {
@@ -197,25 +233,41 @@ func (c *Client) maybeInitPlace(presumedWorkspace workspace.Workspacer) {
 		return
 	}
 
-	// Transients never get placed.
-	if c.transientFor != nil {
+	// We're good, do a placement unless we're already mapped or on a
+	// hidden workspace..
+	if !presumedWorkspace.IsVisible() || !c.isAttrsUnmapped() {
 		return
 	}
+	w := presumedWorkspace.(*workspace.Workspace)
+	floater := w.LayoutFloater()
 
-	// If a user/program position is specified, do not place.
-	if c.nhints.Flags&icccm.SizeHintUSPosition > 0 ||
+	// Transients only get placed when they're centered on their parent.
+	// In that case, the position asked for by the program is ignored.
+	if c.transientFor != nil {
+		if floater.Placement() != layout.PlaceParent {
+			return
+		}
+		if c.nhints.Flags&icccm.SizeHintUSPosition > 0 {
+			return
+		}
+	} else if c.nhints.Flags&icccm.SizeHintUSPosition > 0 ||
 		c.nhints.Flags&icccm.SizeHintPPosition > 0 {
 
+		// If a user/program position is specified, do not place.
 		return
 	}
 
-	// We're good, do a placement unless we're already mapped or on a
-	// hidden workspace..
-	if !presumedWorkspace.IsVisible() || !c.isAttrsUnmapped() {
-		return
+	info := layout.PlaceInfo{}
+	if c.transientFor != nil {
+		info.Parent = c.transientFor
 	}
-	w := presumedWorkspace.(*workspace.Workspace)
-	w.LayoutFloater().InitialPlacement(c)
+	qp, err := xproto.QueryPointer(wm.X.Conn(), wm.X.RootWin()).Reply()
+	if err != nil {
+		logger.Warning.Printf("Could not query pointer: %s", err)
+	} else {
+		info.PointerX, info.PointerY = int(qp.RootX), int(qp.RootY)
+	}
+	floater.InitialPlacement(c, info)
 }
 
 func (c *Client) fetchXProperties() {
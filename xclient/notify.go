@@ -0,0 +1,53 @@
+package xclient
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/event"
+)
+
+// EventInfo describes this client for event subscribers.
+func (c *Client) EventInfo() event.ClientInfo {
+	info := event.ClientInfo{
+		Id:         c.Id(),
+		Name:       c.Name(),
+		Active:     c.IsActive(),
+		Floating:   c.floating,
+		Maximized:  c.maximized,
+		Fullscreen: c.fullscreen,
+		Sticky:     c.sticky,
+		Iconified:  c.iconified,
+		Urgent:     c.demanding,
+	}
+	// The class is only read partway through managing the client.
+	if c.class != nil {
+		info.Class, info.Instance = c.class.Class, c.class.Instance
+	}
+	if c.workspace != nil {
+		info.Workspace = c.workspace.String()
+	}
+	if c.frame != nil {
+		info.X, info.Y, info.Width, info.Height = xrect.Pieces(c.frame.Geom())
+	}
+	return info
+}
+
+// notifyGeometry tells event subscribers that the client has moved or been
+// resized. Nothing is sent while the client is being dragged; the drag
+// handlers call it once the drag is done. Nothing is sent either if the
+// geometry is the same as the last time. (Layouts place every client, even
+// those that don't move.)
+func (c *Client) notifyGeometry() {
+	if c.dragGeom != nil {
+		return
+	}
+	x, y, w, h := xrect.Pieces(c.frame.Geom())
+	if c.notifiedGeom != nil {
+		lx, ly, lw, lh := xrect.Pieces(c.notifiedGeom)
+		if x == lx && y == ly && w == lw && h == lh {
+			return
+		}
+	}
+	c.notifiedGeom = xrect.New(x, y, w, h)
+	event.Notify(event.ChangedClientGeometry{ClientInfo: c.EventInfo()})
+}
@@ -34,6 +34,13 @@ func (p *clientPrompts) destroy() {
 	p.slct.Destroy()
 }
 
+// reload replaces the client's prompt items with new ones from the current
+// prompts.
+func (p *clientPrompts) reload() {
+	p.destroy()
+	*p = p.client.newClientPrompts()
+}
+
 func (p *clientPrompts) updateIcon() {
 	p.cycle.UpdateImage()
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/BurntSushi/xgbutil/icccm"
 
 	"github.com/xuanmingyi/wingo/event"
+	"github.com/xuanmingyi/wingo/hook"
 	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/wm"
 )
@@ -72,6 +73,7 @@ func (c *Client) handleProperty(name string) {
 func (c *Client) refreshIcon() {
 	c.frames.full.UpdateIcon()
 	c.prompts.updateIcon()
+	c.updateTabs()
 }
 
 func (c *Client) refreshName() {
@@ -82,9 +84,17 @@ func (c *Client) refreshName() {
 			c.name = newName
 			c.frames.full.UpdateTitle()
 			c.prompts.updateName()
+			c.updateTabs()
 			ewmh.WmVisibleNameSet(wm.X, c.Id(), c.name)
 
-			event.Notify(event.ChangedClientName{c.Id()})
+			// Don't send the event or fire the hook while the client is still
+			// being managed.
+			if c.workspace != nil {
+				event.Notify(event.ChangedClientName{
+					ClientInfo: c.EventInfo(),
+				})
+				c.FireHook(hook.NameChanged)
+			}
 		}
 	}()
 
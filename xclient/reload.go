@@ -0,0 +1,21 @@
+package xclient
+
+import (
+	"github.com/BurntSushi/xgbutil/mousebind"
+
+	"github.com/xuanmingyi/wingo/wm"
+)
+
+// Reload is called after Wingo's configuration has been reloaded. The mouse
+// bindings of the client and frame windows are re-attached, and the client's
+// frames and prompt items are rebuilt with the new theme. (Frame pieces get
+// their mouse bindings when they're rebuilt.)
+func (c *Client) Reload() {
+	mousebind.Detach(wm.X, c.Id())
+	mousebind.Detach(wm.X, c.frame.Parent().Id)
+	wm.ClientMouseSetup(c)
+	wm.FrameMouseSetup(c, c.frame.Parent().Id)
+
+	c.frames.reload()
+	c.prompts.reload()
+}
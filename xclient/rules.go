@@ -0,0 +1,117 @@
+package xclient
+
+import (
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/rules"
+	"github.com/xuanmingyi/wingo/wm"
+	"github.com/xuanmingyi/wingo/workspace"
+)
+
+// rulesMatch finds the properties of all rules matching this client. It
+// returns nil if no rule matched.
+func (c *Client) rulesMatch() *rules.Properties {
+	props, ok := rules.Match(rules.Window{
+		Class:     c.class.Class,
+		Instance:  c.class.Instance,
+		Title:     c.Name(),
+		Role:      c.role(),
+		Types:     c.winTypes,
+		Transient: c.transientFor != nil,
+	})
+	if !ok {
+		return nil
+	}
+	return &props
+}
+
+// rulesWorkspace returns the workspace that a client should be added to
+// according to its rules. If no rule says anything about the workspace,
+// presumed is returned.
+func (c *Client) rulesWorkspace(props *rules.Properties,
+	presumed workspace.Workspacer) workspace.Workspacer {
+
+	if props.Sticky != nil {
+		if *props.Sticky {
+			return wm.StickyWrk
+		}
+		if _, ok := presumed.(*workspace.Sticky); ok {
+			presumed = wm.Workspace()
+		}
+	}
+	if len(props.Workspace) > 0 {
+		if wrk := wm.Heads.Workspaces.Find(props.Workspace); wrk != nil {
+			return wrk
+		}
+		logger.Warning.Printf("Workspace '%s' from a rule for '%s' does "+
+			"not exist.", props.Workspace, c)
+	} else if props.Head != nil {
+		setPresumed := func(w *workspace.Workspace) {
+			presumed = w
+		}
+		wm.Heads.WithVisibleWorkspace(*props.Head, setPresumed)
+	}
+	return presumed
+}
+
+// rulesInit is called while a client is being managed (before it is placed
+// and added to a workspace) to apply the properties found in its rules.
+// It returns true if the rules gave the client a geometry, in which case it
+// shouldn't be placed.
+func (c *Client) rulesInit(props *rules.Properties,
+	wrk workspace.Workspacer) bool {
+
+	if len(props.Frame) > 0 {
+		c.frames.set(c.frameByName(props.Frame))
+	}
+	if props.Floating != nil {
+		c.floating = *props.Floating
+	}
+	if props.Opacity != nil {
+		ewmh.WmWindowOpacitySet(wm.X, c.frame.Parent().Id, *props.Opacity)
+	}
+	if props.Geom == nil {
+		return false
+	}
+
+	// The geometry is relative to the head of the workspace. If the
+	// workspace isn't visible, the geometry is saved for when it is.
+	headGeom := wrk.HeadGeom()
+	if !wrk.IsVisible() || headGeom == nil {
+		headGeom = wm.Workspace().HeadGeom()
+	}
+	x, y, w, h := props.Geom[0], props.Geom[1], props.Geom[2], props.Geom[3]
+	if w <= 0 {
+		w = c.frame.Geom().Width()
+	}
+	if h <= 0 {
+		h = c.frame.Geom().Height()
+	}
+	x, y = headGeom.X()+x, headGeom.Y()+y
+
+	if wrk.IsVisible() {
+		c.MoveResizeValid(x, y, w, h)
+	} else {
+		c.states["last-floating"] = clientState{
+			geom:      xrect.New(x, y, w, h),
+			headGeom:  xrect.New(xrect.Pieces(headGeom)),
+			frame:     c.frame,
+			maximized: false,
+		}
+	}
+	return true
+}
+
+// rulesFinish is called once a client has been added to its workspace, to
+// apply the properties that would otherwise be overwritten by the client's
+// initial state.
+func (c *Client) rulesFinish(props *rules.Properties) {
+	if props.SkipTaskbar != nil {
+		c.SkipTaskbarSet(*props.SkipTaskbar)
+	}
+	if props.SkipPager != nil {
+		c.SkipPagerSet(*props.SkipPager)
+	}
+}
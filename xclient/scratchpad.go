@@ -0,0 +1,135 @@
+package xclient
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/wm"
+	"github.com/xuanmingyi/wingo/workspace"
+)
+
+// pendingScratchpads holds the names given to ScratchpadExpect. The next new
+// client that matches one of them becomes a scratchpad client.
+var pendingScratchpads = make([]string, 0)
+
+// ScratchpadSend hides a client in the scratchpad. From then on, the client
+// is a scratchpad client: it always floats, and ScratchpadToggle shows and
+// hides it.
+func (c *Client) ScratchpadSend() {
+	if c.workspace == nil || c.workspace == wm.ScratchWrk {
+		return
+	}
+	if c.sticky {
+		c.unstick()
+	}
+	wasActive := c.IsActive()
+
+	old := c.workspace
+	c.scratchpad = true
+	c.iconified = false
+	c.WorkspaceSet(wm.ScratchWrk)
+	old.Remove(c)
+	c.Unmap()
+	c.addState("_NET_WM_STATE_HIDDEN")
+
+	if wasActive {
+		wm.FocusFallback()
+	}
+}
+
+// ScratchpadShow brings a client in the scratchpad to the active workspace,
+// centers it on its head, raises it above every other client and focuses it.
+func (c *Client) ScratchpadShow() {
+	if c.workspace != wm.ScratchWrk {
+		return
+	}
+	c.scratchpadShow()
+	c.Focus()
+}
+
+func (c *Client) scratchpadShow() {
+	wm.Workspace().Add(c)
+	c.scratchpadPlace()
+}
+
+// scratchpadPlace centers a scratchpad client on the workspace it's shown
+// on, in the layer above other clients.
+func (c *Client) scratchpadPlace() {
+	g := c.frame.Geom()
+	geom := layout.SnapCenter.Geom(c.workspace.Geom(), g.Width(), g.Height())
+	c.LayoutMoveResize(xrect.Pieces(geom))
+	c.stackAbove()
+}
+
+// scratchpadMatch returns true if name is the instance or class of the
+// client (case insensitive) or a part of its name. An empty name matches
+// every client.
+func (c *Client) scratchpadMatch(name string) bool {
+	name = strings.ToLower(name)
+	return c.matchWmClass([]string{name}) ||
+		strings.Contains(strings.ToLower(c.Name()), name)
+}
+
+// ScratchpadToggle shows the scratchpad client matching name if it's
+// hidden, and hides it if it's shown on the active workspace. A scratchpad
+// client shown on another workspace is brought to the active workspace.
+// False is returned if no scratchpad client matches name.
+func ScratchpadToggle(name string) bool {
+	var c *Client
+	for _, client := range wm.Clients {
+		c2 := client.(*Client)
+		if c2.scratchpad && c2.scratchpadMatch(name) {
+			c = c2
+			break
+		}
+	}
+	if c == nil {
+		return false
+	}
+
+	switch {
+	case c.workspace == wm.ScratchWrk:
+		c.ScratchpadShow()
+	case c.workspace == wm.Workspace() && !c.iconified:
+		c.ScratchpadSend()
+	default:
+		c.ScratchpadSend()
+		c.ScratchpadShow()
+	}
+	return true
+}
+
+// ScratchpadExpect makes the next new client that matches name (see
+// ScratchpadToggle) a scratchpad client, which is shown as soon as it's
+// managed. This is used when a command is run to start a scratchpad client.
+func ScratchpadExpect(name string) {
+	for _, pending := range pendingScratchpads {
+		if pending == name {
+			return
+		}
+	}
+	pendingScratchpads = append(pendingScratchpads, name)
+}
+
+// maybeScratchpad makes a new client a scratchpad client if it matches a
+// name given to ScratchpadExpect.
+func (c *Client) maybeScratchpad() {
+	for i, name := range pendingScratchpads {
+		if !c.scratchpadMatch(name) {
+			continue
+		}
+		pendingScratchpads = append(pendingScratchpads[:i],
+			pendingScratchpads[i+1:]...)
+
+		c.scratchpad = true
+		if wrk, ok := c.workspace.(*workspace.Workspace); ok {
+			wrk.CheckFloatingStatus(c)
+		}
+		if c.workspace.IsVisible() {
+			c.scratchpadPlace()
+		}
+		return
+	}
+}
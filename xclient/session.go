@@ -0,0 +1,378 @@
+package xclient
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xprop"
+	"github.com/BurntSushi/xgbutil/xrect"
+
+	"github.com/xuanmingyi/wingo/frame"
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/misc"
+	"github.com/xuanmingyi/wingo/session"
+	"github.com/xuanmingyi/wingo/wm"
+	"github.com/xuanmingyi/wingo/workspace"
+)
+
+// pendingSession contains the session entries that have been loaded but
+// not yet matched with a client. Every time a client is managed, it looks
+// for an entry in here.
+var pendingSession = session.New()
+
+// SessionSave takes a snapshot of every managed client and writes it to the
+// file at fpath.
+func SessionSave(fpath string) error {
+	s := session.New()
+	for _, client := range wm.Clients {
+		c := client.(*Client)
+		if !c.sessionable() {
+			continue
+		}
+		s.Add(c.sessionEntry())
+	}
+	for _, wrk := range wm.Heads.Workspaces.Wrks {
+		s.AddWorkspace(sessionWorkspaceEntry(wrk))
+	}
+	if err := s.Save(fpath); err != nil {
+		return err
+	}
+	logger.Message.Printf("Saved %d clients to session '%s'.",
+		len(s.Entries), fpath)
+	return nil
+}
+
+// SessionLoad reads the session in the file at fpath. Entries that match
+// clients that are already managed are applied immediately. The rest are kept
+// around until a matching client is managed.
+func SessionLoad(fpath string) error {
+	s, err := session.Load(fpath)
+	if err != nil {
+		return err
+	}
+	pendingSession = s
+
+	for _, client := range wm.Clients {
+		c := client.(*Client)
+		if !c.sessionable() {
+			continue
+		}
+		if e := pendingSession.Take(c.sessionKey()); e != nil {
+			c.sessionRestore(e)
+		}
+	}
+	SessionRestoreLayouts()
+	logger.Message.Printf("Loaded session '%s' (%d clients not yet managed).",
+		fpath, pendingSession.Len())
+	return nil
+}
+
+// SessionRestoreLayouts applies the state of every workspace in the loaded
+// session: the layout in use and the state of each tiling layout. It is
+// called when a session is loaded, and once more when Wingo starts after
+// existing clients have been managed (so that their order can be restored).
+func SessionRestoreLayouts() {
+	for _, e := range pendingSession.WorkspaceEntries() {
+		wrk := wm.Heads.Workspaces.Find(e.Name)
+		if wrk == nil {
+			continue
+		}
+		for name, state := range e.Layouts {
+			wrk.SetLayoutState(name, state)
+		}
+		if len(e.Layout) > 0 && e.Layout != wrk.LayoutName() {
+			wrk.SetLayout(e.Layout)
+		}
+	}
+}
+
+// sessionWorkspaceEntry takes a snapshot of the layouts of a workspace.
+func sessionWorkspaceEntry(wrk *workspace.Workspace) *session.WorkspaceEntry {
+	e := &session.WorkspaceEntry{
+		Name:    wrk.String(),
+		Layout:  wrk.LayoutName(),
+		Layouts: make(map[string]layout.State),
+	}
+	for _, lay := range wrk.AutoTilers() {
+		e.Layouts[lay.Name()] = lay.State()
+	}
+	return e
+}
+
+// sessionable returns true if a client should be saved to and restored from
+// a session. Only normal top-level clients qualify.
+func (c *Client) sessionable() bool {
+	return c.PrimaryType() == TypeNormal && c.transientFor == nil
+}
+
+// sessionTake finds and consumes the pending session entry matching this
+// client, if there is one.
+func (c *Client) sessionTake() *session.Entry {
+	if !c.sessionable() {
+		return nil
+	}
+	return pendingSession.Take(c.sessionKey())
+}
+
+func (c *Client) sessionKey() session.Key {
+	return session.Key{
+		Class:    c.class.Class,
+		Instance: c.class.Instance,
+		Role:     c.role(),
+		Command:  c.command(),
+	}
+}
+
+// role returns the WM_WINDOW_ROLE of this client, or an empty string if it
+// doesn't have one.
+func (c *Client) role() string {
+	role, _ := xprop.PropValStr(
+		xprop.GetProperty(wm.X, c.Id(), "WM_WINDOW_ROLE"))
+	return role
+}
+
+// command returns the command that started this client. WM_COMMAND is tried
+// first, since hardly anyone sets it anymore we fall back to the command line
+// of the process in _NET_WM_PID.
+func (c *Client) command() string {
+	args, err := xprop.PropValStrs(
+		xprop.GetProperty(wm.X, c.Id(), "WM_COMMAND"))
+	if err == nil && len(args) > 0 {
+		return strings.Join(args, " ")
+	}
+
+	pid, err := ewmh.WmPidGet(wm.X, c.Id())
+	if err != nil {
+		return ""
+	}
+	bs, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.Replace(string(bs), "\x00", " ", -1))
+}
+
+func (c *Client) sessionEntry() *session.Entry {
+	e := &session.Entry{
+		Key:       c.sessionKey(),
+		Floating:  c.floating,
+		Sticky:    c.sticky,
+		Maximized: c.maximized,
+		Iconified: c.iconified,
+		Frame:     c.frameName(c.frame),
+
+		Scratchpad: c.scratchpad,
+	}
+	if wrk, ok := c.workspace.(*workspace.Workspace); ok {
+		e.Workspace = wrk.String()
+	}
+	if geom, headGeom := c.floatingGeom(); geom != nil {
+		e.X, e.Y, e.Width, e.Height = xrect.Pieces(geom)
+		if headGeom != nil {
+			e.HeadX, e.HeadY, e.HeadWidth, e.HeadHeight =
+				xrect.Pieces(headGeom)
+		}
+	}
+	return e
+}
+
+// floatingGeom returns the geometry the client would have if it were floating,
+// along with the geometry of the head that it is relative to. The current
+// geometry is used if the client is floating and visible. Otherwise, we look
+// at the saved states. Both are nil if no geometry could be found.
+func (c *Client) floatingGeom() (xrect.Rect, xrect.Rect) {
+	if c.workspace != nil && c.workspace.IsVisible() &&
+		!c.maximized && !c.fullscreen && !c.iconified {
+
+		if _, ok := c.Layout().(layout.Floater); ok {
+			return c.frame.Geom(), c.workspace.HeadGeom()
+		}
+	}
+	for _, name := range []string{
+		"before-maximize", "last-floating", "workspace-switch"} {
+
+		if s, ok := c.states[name]; ok {
+			return s.geom, s.headGeom
+		}
+	}
+	return nil, nil
+}
+
+// sessionState converts a session entry into a client state that can be
+// loaded with LoadState.
+func (c *Client) sessionState(e *session.Entry) clientState {
+	s := clientState{
+		geom:      xrect.New(e.X, e.Y, e.Width, e.Height),
+		headGeom:  nil,
+		frame:     c.frameByName(e.Frame),
+		maximized: false,
+	}
+	if e.HasHeadGeom() {
+		s.headGeom = xrect.New(e.HeadX, e.HeadY, e.HeadWidth, e.HeadHeight)
+	}
+	return s
+}
+
+// sessionWorkspace returns the workspace that a client should be added to
+// according to a session entry. If the entry's workspace no longer exists,
+// presumed is returned.
+func (c *Client) sessionWorkspace(e *session.Entry,
+	presumed workspace.Workspacer) workspace.Workspacer {
+
+	if e.Sticky {
+		return wm.StickyWrk
+	}
+	if wrk := wm.Heads.Workspaces.Find(e.Workspace); wrk != nil {
+		return wrk
+	}
+	return presumed
+}
+
+// sessionInit is called while a client is being managed (before it is added
+// to a workspace) to inject the state found in a session entry.
+func (c *Client) sessionInit(e *session.Entry) {
+	c.floating = e.Floating
+	c.iconified = e.Iconified
+	c.scratchpad = e.Scratchpad
+	c.frames.set(c.frameByName(e.Frame))
+	if e.HasGeom() {
+		c.states["last-floating"] = c.sessionState(e)
+	}
+}
+
+// sessionFinish is called once a client restored from a session entry has
+// been added to its workspace.
+func (c *Client) sessionFinish(e *session.Entry) {
+	if e.InScratchpad() {
+		c.ScratchpadSend()
+		return
+	}
+	if e.Iconified {
+		c.addState("_NET_WM_STATE_HIDDEN")
+	}
+	if e.Maximized {
+		c.Maximize()
+	}
+}
+
+// sessionRestore applies a session entry to a client that is already managed.
+func (c *Client) sessionRestore(e *session.Entry) {
+	c.scratchpad = e.Scratchpad
+	if e.InScratchpad() {
+		c.ScratchpadSend()
+		return
+	}
+
+	if e.Sticky {
+		c.stick()
+	} else {
+		if c.sticky {
+			c.unstick()
+		}
+		if wrk := wm.Heads.Workspaces.Find(e.Workspace); wrk != nil {
+			wrk.Add(c)
+		}
+	}
+	if e.Floating {
+		c.Float()
+	} else {
+		c.Unfloat()
+	}
+
+	c.EnsureUnmax()
+	c.frames.set(c.frameByName(e.Frame))
+	if e.HasGeom() {
+		c.states["last-floating"] = c.sessionState(e)
+		if _, ok := c.Layout().(layout.Floater); ok {
+			c.LoadState("last-floating")
+		}
+	}
+	if e.Maximized {
+		c.Maximize()
+	}
+	if e.Iconified != c.iconified {
+		c.IconifyToggle()
+	}
+}
+
+// frameName returns the name of one of the client's frames. It is the inverse
+// of frameByName.
+func (c *Client) frameName(f frame.Frame) string {
+	switch f.(type) {
+	case *frame.Full:
+		return "full"
+	case *frame.Borders:
+		return "borders"
+	case *frame.Slim:
+		return "slim"
+	case *frame.Nada:
+		return "nada"
+	}
+	return ""
+}
+
+// frameByName returns the client frame with the given name. An unknown
+// name results in the current frame.
+func (c *Client) frameByName(name string) frame.Frame {
+	switch name {
+	case "full":
+		return c.frames.full
+	case "borders":
+		return c.frames.borders
+	case "slim":
+		return c.frames.slim
+	case "nada":
+		return c.frames.nada
+	}
+	return c.frame
+}
+
+// SessionFile returns the absolute path of the session file called name.
+// Relative names are resolved in the user's data directory. If name is
+// empty, the "session_file" option is used instead.
+func SessionFile(name string) (string, error) {
+	if len(name) == 0 {
+		name = wm.Config.SessionFile
+	}
+	if len(name) == 0 {
+		return "", fmt.Errorf("no session file has been configured")
+	}
+	return misc.UserDataFile(name)
+}
+
+// SessionLoadStartup loads the session named by the "session_file" option.
+// It is called once when Wingo starts, before existing clients are managed.
+// A missing session file is not an error.
+func SessionLoadStartup() {
+	if len(wm.Config.SessionFile) == 0 {
+		return
+	}
+	fpath, err := SessionFile("")
+	if err != nil {
+		logger.Warning.Printf("Could not find session file: %s", err)
+		return
+	}
+	if err := SessionLoad(fpath); err != nil && !os.IsNotExist(err) {
+		logger.Warning.Printf("Could not load session '%s': %s", fpath, err)
+	}
+}
+
+// SessionSaveShutdown saves the session named by the "session_file" option.
+// It is called when Wingo quits or restarts.
+func SessionSaveShutdown() {
+	if len(wm.Config.SessionFile) == 0 {
+		return
+	}
+	fpath, err := SessionFile("")
+	if err != nil {
+		logger.Warning.Printf("Could not find session file: %s", err)
+		return
+	}
+	if err := SessionSave(fpath); err != nil {
+		logger.Warning.Printf("Could not save session '%s': %s", fpath, err)
+	}
+}
@@ -0,0 +1,165 @@
+package xclient
+
+import (
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+
+	"github.com/xuanmingyi/wingo/wm"
+)
+
+// snapper adjusts the geometry of a client being dragged so that its edges
+// snap to the edges of workareas and other clients, and resist being pushed
+// past the edges of heads. The edges are collected once when the drag
+// begins, since nothing else moves during a drag.
+type snapper struct {
+	threshold, resistance int
+
+	// Vertical edges (with an x position) and horizontal edges (with a y
+	// position) that the client snaps to.
+	xlines, ylines []snapLine
+
+	// The geometry of every head, used for edge resistance.
+	heads []xrect.Rect
+
+	// The position the client would be at if it weren't snapped. The
+	// client is moved relative to this, so that it can leave an edge.
+	rawX, rawY int
+
+	// Where the client is snapped to when it's dropped (or nil), and the
+	// window showing it.
+	drop    xrect.Rect
+	preview *xwindow.Window
+}
+
+// snapLine is an edge at pos on one axis, which spans from lo to hi on the
+// other axis.
+type snapLine struct {
+	pos, lo, hi int
+}
+
+func (c *Client) newSnapper() *snapper {
+	s := &snapper{
+		threshold:  wm.Config.SnapThreshold,
+		resistance: wm.Config.EdgeResistance,
+	}
+	for _, wrk := range wm.Heads.VisibleWorkspaces() {
+		s.addRect(wrk.Geom())
+		s.heads = append(s.heads, wrk.HeadGeom())
+	}
+	if wm.Config.SnapToWindows {
+		for _, client := range wm.Clients {
+			c2 := client.(*Client)
+			if c2 != c && c2.IsMapped() {
+				s.addRect(c2.frame.Geom())
+			}
+		}
+	}
+	s.rawX, s.rawY = c.frame.Geom().X(), c.frame.Geom().Y()
+	return s
+}
+
+// addRect adds the four edges of r to the edges snapped to.
+func (s *snapper) addRect(r xrect.Rect) {
+	x, y, w, h := xrect.Pieces(r)
+	s.xlines = append(s.xlines,
+		snapLine{x, y, y + h}, snapLine{x + w, y, y + h})
+	s.ylines = append(s.ylines,
+		snapLine{y, x, x + w}, snapLine{y + h, x, x + w})
+}
+
+// move returns the position a client with the given geometry should be
+// moved to.
+func (s *snapper) move(x, y, w, h int) (int, int) {
+	x += s.shift(s.xlines, s.headEdges(true), x, x+w, y, y+h, true, true)
+	y += s.shift(s.ylines, s.headEdges(false), y, y+h, x, x+w, true, true)
+	return x, y
+}
+
+// resize returns the geometry a client should be resized to. Only the
+// edges that are being dragged are snapped: the left or right edge
+// depending on xs, and the top or bottom edge depending on ys.
+func (s *snapper) resize(x, y, w, h int, xs, ws, ys, hs bool) (int, int,
+	int, int) {
+
+	if ws {
+		d := s.shift(s.xlines, s.headEdges(true), x, x+w, y, y+h, xs, !xs)
+		if xs {
+			x, w = x+d, w-d
+		} else {
+			w += d
+		}
+	}
+	if hs {
+		d := s.shift(s.ylines, s.headEdges(false), y, y+h, x, x+w, ys, !ys)
+		if ys {
+			y, h = y+d, h-d
+		} else {
+			h += d
+		}
+	}
+	return x, y, w, h
+}
+
+// headEdges returns the low and high edges of every head along the x axis
+// (if horizontal is true) or the y axis.
+func (s *snapper) headEdges(horizontal bool) [][2]snapLine {
+	edges := make([][2]snapLine, len(s.heads))
+	for i, hd := range s.heads {
+		x, y, w, h := xrect.Pieces(hd)
+		if horizontal {
+			edges[i] = [2]snapLine{{x, y, y + h}, {x + w, y, y + h}}
+		} else {
+			edges[i] = [2]snapLine{{y, x, x + w}, {y + h, x, x + w}}
+		}
+	}
+	return edges
+}
+
+// shift returns how far the edges of a window should be moved along one
+// axis. The window goes from lo to hi on that axis and from alo to ahi on
+// the other axis. Only the low edge is considered if moveLo is true, and
+// only the high edge if moveHi is true.
+//
+// Edge resistance comes first: an edge that has gone past the edge of a
+// head by no more than the resistance is pulled back to it. Otherwise, the
+// closest snap line within the threshold is used.
+func (s *snapper) shift(lines []snapLine, heads [][2]snapLine,
+	lo, hi, alo, ahi int, moveLo, moveHi bool) int {
+
+	if s.resistance > 0 {
+		for _, hd := range heads {
+			if !acrossOverlap(hd[0], alo, ahi) {
+				continue
+			}
+			if moveHi && hi > hd[1].pos && hi <= hd[1].pos+s.resistance {
+				return hd[1].pos - hi
+			}
+			if moveLo && lo < hd[0].pos && lo >= hd[0].pos-s.resistance {
+				return hd[0].pos - lo
+			}
+		}
+	}
+
+	best := s.threshold + 1
+	for _, line := range lines {
+		if !acrossOverlap(line, alo, ahi) {
+			continue
+		}
+		if d := line.pos - lo; moveLo && abs(d) < abs(best) {
+			best = d
+		}
+		if d := line.pos - hi; moveHi && abs(d) < abs(best) {
+			best = d
+		}
+	}
+	if abs(best) > s.threshold {
+		return 0
+	}
+	return best
+}
+
+// acrossOverlap returns true if line overlaps the segment from lo to hi on
+// the other axis.
+func acrossOverlap(line snapLine, lo, hi int) bool {
+	return line.lo < hi && lo < line.hi
+}
@@ -0,0 +1,147 @@
+package xclient
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xrect"
+	"github.com/BurntSushi/xgbutil/xwindow"
+
+	"github.com/xuanmingyi/wingo/layout"
+	"github.com/xuanmingyi/wingo/logger"
+	"github.com/xuanmingyi/wingo/wm"
+)
+
+const (
+	// snapEdgeZone is how close (in pixels) the pointer must be to the edge
+	// of a head for a client dropped there to be snapped to a half.
+	snapEdgeZone = 2
+
+	// snapCornerZone is how close the pointer must be to a corner of a head,
+	// along the edge it's on, for a client dropped there to be snapped to a
+	// quarter instead.
+	snapCornerZone = 50
+
+	// snapPreviewOpacity is the opacity of the preview shown while a client
+	// is dragged to an edge. It only has an effect with a compositor.
+	snapPreviewOpacity = 0.35
+)
+
+// SnapTo tiles a floating client to a region of the workarea of its head.
+// The geometry the client had before it was first snapped is saved in the
+// "before-snap" state, so that its size can be restored when it's dragged
+// away. SnapCenter centers the client using that size.
+func (c *Client) SnapTo(region layout.SnapRegion) {
+	if !c.canMaxUnmax() {
+		return
+	}
+	c.EnsureUnmax()
+
+	width, height := c.frame.Geom().Width(), c.frame.Geom().Height()
+	if s, ok := c.states["before-snap"]; ok {
+		width, height = s.geom.Width(), s.geom.Height()
+	}
+	c.snapTo(region.Geom(c.workspace.Geom(), width, height))
+}
+
+func (c *Client) snapTo(geom xrect.Rect) {
+	if !c.HasState("before-snap") {
+		c.SaveState("before-snap")
+	}
+	c.LayoutMoveResize(xrect.Pieces(geom))
+}
+
+// unsnap gives a snapped client that has started to be dragged the size it
+// had before it was snapped. The pointer (at rx) stays over the same part
+// of the client horizontally.
+func (c *Client) unsnap(rx int) {
+	s, ok := c.states["before-snap"]
+	if !ok {
+		return
+	}
+	delete(c.states, "before-snap")
+
+	geom := c.frame.Geom()
+	w, h := s.geom.Width(), s.geom.Height()
+	x := rx - (rx-geom.X())*w/geom.Width()
+	c.LayoutMoveResize(x, geom.Y(), w, h)
+
+	c.dragGeom = xrect.New(x, geom.Y(), w, h)
+	c.snap.rawX, c.snap.rawY = x, geom.Y()
+}
+
+// dragSnapRegion returns the geometry of the region that a client dropped
+// with the pointer at (px, py) should be snapped to, or nil if the pointer
+// isn't at the edge of a head.
+func dragSnapRegion(px, py int) xrect.Rect {
+	wrk := wm.Heads.FindMostOverlap(xrect.New(px, py, 1, 1))
+	if wrk == nil {
+		return nil
+	}
+	hx, hy, hw, hh := xrect.Pieces(wrk.HeadGeom())
+	left, right := px < hx+snapEdgeZone, px >= hx+hw-snapEdgeZone
+	top, bottom := py < hy+snapEdgeZone, py >= hy+hh-snapEdgeZone
+	nearLeft, nearRight := px < hx+snapCornerZone, px >= hx+hw-snapCornerZone
+	nearTop, nearBottom := py < hy+snapCornerZone, py >= hy+hh-snapCornerZone
+
+	var region layout.SnapRegion
+	switch {
+	case (left && nearTop) || (top && nearLeft):
+		region = layout.SnapTopLeft
+	case (right && nearTop) || (top && nearRight):
+		region = layout.SnapTopRight
+	case (left && nearBottom) || (bottom && nearLeft):
+		region = layout.SnapBottomLeft
+	case (right && nearBottom) || (bottom && nearRight):
+		region = layout.SnapBottomRight
+	case left:
+		region = layout.SnapLeft
+	case right:
+		region = layout.SnapRight
+	case top:
+		region = layout.SnapTop
+	case bottom:
+		region = layout.SnapBottom
+	default:
+		return nil
+	}
+	return region.Geom(wrk.Geom(), 0, 0)
+}
+
+// showPreview shows a translucent window at geom, just below the client,
+// where the client will be snapped if it's dropped.
+func (s *snapper) showPreview(c *Client, geom xrect.Rect) {
+	s.drop = geom
+	if s.preview == nil {
+		win, err := xwindow.Generate(wm.X)
+		if err != nil {
+			logger.Warning.Printf("Could not create snap preview: %s", err)
+			return
+		}
+		theme := wm.Theme.Full.FrameTheme()
+		win.Create(wm.X.RootWin(), 0, 0, 1, 1,
+			xproto.CwBackPixel|xproto.CwOverrideRedirect,
+			uint32(theme.ABorderColor.Int()), 1)
+		ewmh.WmWindowOpacitySet(wm.X, win.Id, snapPreviewOpacity)
+		s.preview = win
+	}
+	s.preview.MoveResize(xrect.Pieces(geom))
+	s.preview.Map()
+	s.preview.StackSibling(c.TopWin().Id, xproto.StackModeBelow)
+}
+
+// hidePreview hides the preview window, if it's shown.
+func (s *snapper) hidePreview() {
+	s.drop = nil
+	if s.preview != nil {
+		s.preview.Unmap()
+	}
+}
+
+// destroyPreview destroys the preview window, if it was ever created.
+func (s *snapper) destroyPreview() {
+	if s.preview != nil {
+		s.preview.Destroy()
+		s.preview = nil
+	}
+}
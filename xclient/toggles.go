@@ -3,7 +3,9 @@ package xclient
 import (
 	"time"
 
+	"github.com/xuanmingyi/wingo/event"
 	"github.com/xuanmingyi/wingo/frame"
+	"github.com/xuanmingyi/wingo/hook"
 	"github.com/xuanmingyi/wingo/layout"
 	"github.com/xuanmingyi/wingo/stack"
 	"github.com/xuanmingyi/wingo/wm"
@@ -114,6 +116,7 @@ func (c *Client) unstick() {
 	wm.Workspace().Add(c)
 
 	c.removeState("_NET_WM_STATE_STICKY")
+	event.Notify(event.UnstuckClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) stick() {
@@ -121,14 +124,23 @@ func (c *Client) stick() {
 		return
 	}
 
+	// A hidden scratchpad client is shown first, so that it sticks to the
+	// active workspace.
+	if c.workspace == wm.ScratchWrk {
+		c.scratchpadShow()
+	}
+
 	c.sticky = true
 	if c.workspace != nil {
-		c.workspace.(*workspace.Workspace).CheckFloatingStatus(c)
+		if wrk, ok := c.workspace.(*workspace.Workspace); ok {
+			wrk.CheckFloatingStatus(c)
+		}
 		c.workspace.Remove(c)
 	}
 	c.WorkspaceSet(wm.StickyWrk)
 
 	c.addState("_NET_WM_STATE_STICKY")
+	event.Notify(event.StuckClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) FullscreenToggle() {
@@ -165,6 +177,8 @@ func (c *Client) Fullscreened() {
 
 	c.layer = stack.LayerFullscreen
 	c.Raise()
+
+	event.Notify(event.FullscreenedClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) Unfullscreened() {
@@ -188,6 +202,8 @@ func (c *Client) Unfullscreened() {
 
 	c.layer = stack.LayerDefault
 	c.Raise()
+
+	event.Notify(event.UnfullscreenedClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) MaximizeToggle() {
@@ -243,6 +259,8 @@ func (c *Client) maximize() {
 
 	g := c.Workspace().Geom()
 	c.LayoutMoveResize(g.X(), g.Y(), g.Width(), g.Height())
+
+	event.Notify(event.MaximizedClient{ClientInfo: c.EventInfo()})
 }
 
 func (c *Client) unmaximize() {
@@ -254,6 +272,8 @@ func (c *Client) unmaximize() {
 		c.removeState("_NET_WM_STATE_MAXIMIZE_HORZ")
 		c.removeState("_NET_WM_STATE_MAXIMIZE_VERT")
 		c.frames.unmaximize()
+
+		event.Notify(event.UnmaximizedClient{ClientInfo: c.EventInfo()})
 	}
 }
 
@@ -314,6 +334,8 @@ func (c *Client) attnStart() {
 	}()
 
 	c.addState("_NET_WM_STATE_DEMANDS_ATTENTION")
+	event.Notify(event.UrgentClient{ClientInfo: c.EventInfo()})
+	c.FireHook(hook.Urgent)
 }
 
 func (c *Client) attnStop() {
@@ -332,4 +354,5 @@ func (c *Client) attnStop() {
 	}
 
 	c.removeState("_NET_WM_STATE_DEMANDS_ATTENTION")
+	event.Notify(event.UnurgentClient{ClientInfo: c.EventInfo()})
 }
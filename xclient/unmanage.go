@@ -1,6 +1,7 @@
 package xclient
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/BurntSushi/xgb/xproto"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/xuanmingyi/wingo/event"
 	"github.com/xuanmingyi/wingo/focus"
+	"github.com/xuanmingyi/wingo/hook"
 	"github.com/xuanmingyi/wingo/logger"
 	"github.com/xuanmingyi/wingo/stack"
 	"github.com/xuanmingyi/wingo/wm"
@@ -28,10 +30,7 @@ func (c *Client) unmanage() {
 		logger.Message.Printf("Unmanaging client: %s", c)
 	}
 
-	infoWorkspace := c.workspace.String()
-	infoClass := c.Class().Class
-	infoInstance := c.Class().Instance
-	infoName := c.Name()
+	info := c.EventInfo()
 
 	c.frame.Unmap()
 	c.win.Detach()
@@ -49,12 +48,10 @@ func (c *Client) unmanage() {
 		wm.Heads.ApplyStruts(wm.Clients)
 	}
 
-	event.Notify(event.UnmanagedClient{
-		Id:        c.Id(),
-		Name:      infoName,
-		Workspace: infoWorkspace,
-		Class:     infoClass,
-		Instance:  infoInstance,
+	event.Notify(event.UnmanagedClient{ClientInfo: info})
+	hook.Fire(hook.Unmanaged, hook.Args{
+		Client:    fmt.Sprintf("%d", info.Id),
+		Workspace: info.Workspace,
 	})
 }
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/BurntSushi/xgbutil/ewmh"
 
+	"github.com/xuanmingyi/wingo/event"
 	"github.com/xuanmingyi/wingo/wm"
 	"github.com/xuanmingyi/wingo/workspace"
 )
@@ -15,6 +16,7 @@ import (
 func (c *Client) ShouldForceFloating() bool {
 	return c.floating ||
 		c.sticky ||
+		c.scratchpad ||
 		c.fullscreen ||
 		c.transientFor != nil ||
 		c.PrimaryType() != TypeNormal ||
@@ -27,11 +29,17 @@ func (c *Client) Workspace() workspace.Workspacer {
 }
 
 func (c *Client) WorkspaceSet(newWrk workspace.Workspacer) {
+	// A client leaving the scratchpad is no longer hidden, however it left.
+	if c.workspace == wm.ScratchWrk && newWrk != wm.ScratchWrk {
+		c.removeState("_NET_WM_STATE_HIDDEN")
+	}
 	c.workspace = newWrk
 
 	switch wrk := c.workspace.(type) {
 	case *workspace.Sticky:
 		ewmh.WmDesktopSet(wm.X, c.Id(), 0xFFFFFFFF)
+	case *workspace.Scratchpad:
+		// The scratchpad isn't a desktop, so the last one is kept.
 	case *workspace.Workspace:
 		ewmh.WmDesktopSet(wm.X, c.Id(), uint(wm.Heads.GlobalIndex(wrk)))
 	default:
@@ -44,8 +52,10 @@ func (c *Client) IconifyToggle() {
 
 	if c.Iconified() {
 		c.addState("_NET_WM_STATE_HIDDEN")
+		event.Notify(event.IconifiedClient{ClientInfo: c.EventInfo()})
 	} else {
 		c.removeState("_NET_WM_STATE_HIDDEN")
+		event.Notify(event.DeiconifiedClient{ClientInfo: c.EventInfo()})
 	}
 }
 